@@ -1,5 +1,7 @@
 package h3
 
+import "math"
+
 const HEX_RANGE_SUCCESS = 0
 const HEX_RANGE_PENTAGON = 1
 const HEX_RANGE_K_SUBSEQUENCE = 2
@@ -94,6 +96,44 @@ var NEW_ADJUSTMENT_III = [7][7]Direction{
  */
 func maxKringSize(k int) int { return 3*k*(k+1) + 1 }
 
+/**
+ * maxGridDistance returns an upper bound of the grid distance between any two
+ * cells of the given resolution: half of the circumference of the earth, the
+ * longest great circle arc between their centers, over a quarter of the
+ * sqrt(3) edge lengths between the centers of neighboring hexagons. One
+ * factor of two allows for the distortion of the grid, where cells are down
+ * to about half as wide, and the other for a path of cells zigzagging along
+ * the arc. It is about four times the actual diameter of the grid, which the
+ * tests check at coarse resolutions.
+ *
+ * @param res Resolution (0-15).
+ */
+func maxGridDistance(res int) int {
+	return int(math.Ceil(4 * M_PI * EARTH_RADIUS_KM / (math.Sqrt(3) * edgeLengthKm(res))))
+}
+
+/**
+ * _gridDiskSize clamps k to the largest possible grid distance at the
+ * resolution, which does not change the disk, and returns the clamped k
+ * together with the size of the buffer the disk needs. The size is smaller
+ * than maxKringSize(k) when the disk may cover the whole sphere.
+ *
+ * @param k k >= 0
+ * @param res Resolution (0-15).
+ * @return The clamped k and the size, which may be more than
+ *         MAX_SLICE_CELLS.
+ */
+func _gridDiskSize(k int, res int) (int, float64) {
+	if maxK := maxGridDistance(res); k > maxK {
+		k = maxK
+	}
+	size := 3*float64(k)*float64(k+1) + 1
+	if cells := float64(numHexagons(res)); size > cells {
+		size = cells
+	}
+	return k, size
+}
+
 /**
  * k-rings produces indices within k distance of the origin index.
  *
@@ -132,58 +172,147 @@ func kRingDistances(origin H3Index, k int, out []H3Index, distances []int) {
 	// Optimistically try the faster hexRange algorithm first
 	failed := hexRangeDistances(origin, k, out, distances)
 	if failed != 0 {
-		// Fast algo failed, fall back to slower, correct algo
-		// and also wipe out array because contents untrustworthy
-		out = make([]H3Index, maxIdx) // todo
+		// Fast algo failed, fall back to the breadth first search, which
+		// is correct around pentagons, and also wipe out array because
+		// contents untrustworthy
+		for i := 0; i < maxIdx; i++ {
+			out[i] = H3_INVALID_INDEX
+			if len(distances) > i {
+				distances[i] = 0
+			}
+		}
 
-		_kRingInternal(origin, k, out, distances, maxIdx, 0)
+		_gridDiskDistancesBfs(origin, k, out, distances, nil)
 	}
 }
 
 /**
- * Internal helper function called recursively for kRingDistances.
+ * Breadth first search for kRingDistances, correct in the presence of
+ * pentagons and pentagonal distortion.
  *
- * Adds the origin index to the output set (treating it as a hash set)
- * and recurses to its neighbors, if needed.
+ * Every index is expanded exactly once, ring by ring, by stepping in all six
+ * directions with fresh rotations. Stepping into the deleted k subsequence of
+ * a pentagon yields 0 and is skipped, so the search naturally wraps around
+ * pentagons.
  *
- * @param origin
- * @param k Maximum distance to move from the origin.
- * @param out Array treated as a hash set, elements being either or H3Index 0.
- * @param distances Scratch area, with elements paralleling the out array.
- * Elements indicate ijk distance from the origin index to the output index.
- * @param maxIdx Size of out and scratch arrays (must be maxKringSize(k))
- * @param curK Current distance from the origin.
+ * Output is placed in the provided array in order of increasing distance
+ * from the origin, and the remainder of the array is left untouched.
+ *
+ * @param origin Origin location.
+ * @param k k >= 0
+ * @param out Array which must be of size maxKringSize(k).
+ * @param distances Null or array which must be of size maxKringSize(k).
+ * @param budget Checked for each index expanded, or nil.
+ * @return The number of indexes written to out, up to where the budget ran
+ *         out.
  */
-func _kRingInternal(origin H3Index, k int, out []H3Index, distances []int, maxIdx int, curK int) {
-	if origin == 0 {
-		return
-	}
+func _gridDiskDistancesBfs(origin H3Index, k int, out []H3Index, distances []int, budget *workBudget) int {
+	seen := make(map[H3Index]struct{}, len(out))
 
-	// Put origin in the output array. out is used as a hash set.
-	off := uint64(origin) % uint64(maxIdx)
-	for out[off] != 0 && out[off] != origin {
-		off = (off + 1) % uint64(maxIdx)
+	idx := 0
+	out[idx] = origin
+	if len(distances) > idx {
+		distances[idx] = 0
+	}
+	idx++
+	seen[origin] = struct{}{}
+
+	// out[ringStart:idx] holds the indexes of the last completed ring
+	ringStart := 0
+	for ring := 1; ring <= k; ring++ {
+		ringEnd := idx
+		for i := ringStart; i < ringEnd; i++ {
+			if !budget.check(idx) {
+				return idx
+			}
+			for dir := 0; dir < 6; dir++ {
+				rotations := 0
+				neighbor := h3NeighborRotations(out[i], DIRECTIONS[dir], &rotations)
+				if neighbor == H3_INVALID_INDEX {
+					// deleted k subsequence of a pentagon
+					continue
+				}
+				if _, ok := seen[neighbor]; ok {
+					continue
+				}
+				seen[neighbor] = struct{}{}
+				out[idx] = neighbor
+				if len(distances) > idx {
+					distances[idx] = ring
+				}
+				idx++
+			}
+		}
+		if idx == ringEnd {
+			// nothing new found, the whole sphere is covered
+			break
+		}
+		ringStart = ringEnd
 	}
 
-	// We either got a free slot in the hash set or hit a duplicate
-	// We might need to process the duplicate anyways because we got
-	// here on a longer path before.
-	if out[off] == origin && distances[off] <= curK {
-		return
+	return idx
+}
+
+/**
+ * GridDisk produces the cells within grid distance k of the origin cell,
+ * including the origin itself.
+ *
+ * Unlike kRing, the result never contains zero entries: pentagons and
+ * pentagonal distortion areas are handled by falling back to a breadth first
+ * search whenever the fast hexRange algorithm fails.
+ *
+ * @param origin Origin cell.
+ * @param k Distance, k >= 0.
+ * @return The cells in order of increasing distance from the origin.
+ */
+func GridDisk(origin H3Index, k int) ([]H3Index, error) {
+	out, _, err := GridDiskDistances(origin, k)
+	return out, err
+}
+
+/**
+ * GridDiskDistances produces the cells within grid distance k of the origin
+ * cell together with the grid distance of each of them from the origin.
+ *
+ * @param origin Origin cell.
+ * @param k Distance, k >= 0.
+ * @return The cells in order of increasing distance from the origin, and a
+ *         parallel slice of their distances. ErrDomain is returned when the
+ *         disk needs room for more than MAX_SLICE_CELLS cells.
+ */
+func GridDiskDistances(origin H3Index, k int) ([]H3Index, []int, error) {
+	if !h3IsValid(origin) {
+		return nil, nil, ErrInvalidIndex
+	}
+	if k < 0 {
+		return nil, nil, ErrDomain
+	}
+	k, size := _gridDiskSize(k, H3_GET_RESOLUTION(origin))
+	if size > MAX_SLICE_CELLS {
+		return nil, nil, ErrDomain
 	}
-	out[off] = origin
-	distances[off] = curK
 
-	// Base case: reached an index k away from the origin.
-	if curK >= k {
-		return
+	maxIdx := int(size)
+	out := make([]H3Index, maxIdx)
+	distances := make([]int, maxIdx)
+	if maxIdx < maxKringSize(k) {
+		// hexRange would run past the end of the buffer before noticing
+		// that the disk wraps around the sphere
+		_gridDiskDistancesBfs(origin, k, out, distances, nil)
+	} else {
+		kRingDistances(origin, k, out, distances)
 	}
 
-	// Recurse to all neighbors in no particular order.
-	for i := 0; i < 6; i++ {
-		rotations := 0
-		_kRingInternal(h3NeighborRotations(origin, DIRECTIONS[i], &rotations), k, out, distances, maxIdx, curK+1)
+	// Squeeze out the slots left empty by pentagons
+	n := 0
+	for i := 0; i < maxIdx; i++ {
+		if out[i] != H3_INVALID_INDEX {
+			out[n] = out[i]
+			distances[n] = distances[i]
+			n++
+		}
 	}
+	return out[:n], distances[:n], nil
 }
 
 /**
@@ -552,6 +681,51 @@ func hexRing(origin H3Index, k int, out []H3Index) int {
 	return HEX_RANGE_SUCCESS
 }
 
+/**
+ * GridRing produces the hollow ring of cells at exactly grid distance k from
+ * the origin cell.
+ *
+ * When hexRing fails because of pentagonal distortion the ring is taken from
+ * GridDiskDistances instead, in which case the cells are ordered by the
+ * breadth first search rather than by walking around the ring.
+ *
+ * @param origin Origin cell.
+ * @param k Distance, k >= 0.
+ * @return The cells of the ring.
+ */
+func GridRing(origin H3Index, k int) ([]H3Index, error) {
+	if !h3IsValid(origin) {
+		return nil, ErrInvalidIndex
+	}
+	if k < 0 {
+		return nil, ErrDomain
+	}
+	if k > maxGridDistance(H3_GET_RESOLUTION(origin)) {
+		return []H3Index{}, nil
+	}
+
+	size := 1
+	if k > 0 {
+		size = 6 * k
+	}
+	out := make([]H3Index, size)
+	if hexRing(origin, k, out) == HEX_RANGE_SUCCESS {
+		return out, nil
+	}
+
+	disk, distances, err := GridDiskDistances(origin, k)
+	if err != nil {
+		return nil, err
+	}
+	out = out[:0]
+	for i := range disk {
+		if distances[i] == k {
+			out = append(out, disk[i])
+		}
+	}
+	return out, nil
+}
+
 /**
  * maxPolyfillSize returns the number of hexagons to allocate space for when
  * performing a polyfill on the given GeoJSON-like data structure.
@@ -598,7 +772,7 @@ func maxPolyfillSize(geoPolygon *GeoPolygon, res int) int {
  */
 func polyfill(geoPolygon *GeoPolygon, res int, out []H3Index) {
 	// TODO: Eliminate this wrapper with the H3 4.0.0 release
-	failure := _polyfillInternal(geoPolygon, res, out)
+	failure := _polyfillInternal(geoPolygon, res, out, nil)
 	// The polyfill algorithm can theoretically fail if the allocated memory is
 	// not large enough for the polygon, but this should be impossible given the
 	// conservative overestimation of the number of hexagons possible.
@@ -612,6 +786,75 @@ func polyfill(geoPolygon *GeoPolygon, res int, out []H3Index) {
 	// LCOV_EXCL_STOP
 }
 
+/**
+ * _geofenceIsFinite returns whether all the vertices of the geofence are
+ * finite numbers.
+ */
+func _geofenceIsFinite(geofence *Geofence) bool {
+	for i := 0; i < geofence.numVerts; i++ {
+		if !_geoIsFinite(&geofence.verts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+/**
+ * _validatePolygonToCells checks the input of a polyfill.
+ *
+ * @param geoPolygon The polygon, which may be empty.
+ * @param res The resolution of the cells.
+ * @return ErrDomain for a resolution out of range or non finite vertices.
+ */
+func _validatePolygonToCells(geoPolygon *GeoPolygon, res int) error {
+	if res < 0 || res > MAX_H3_RES {
+		return ErrDomain
+	}
+	if !_geofenceIsFinite(&geoPolygon.geofence) {
+		return ErrDomain
+	}
+	for i := 0; i < geoPolygon.numHoles; i++ {
+		if !_geofenceIsFinite(&geoPolygon.holes[i]) {
+			return ErrDomain
+		}
+	}
+	return nil
+}
+
+/**
+ * PolygonToCells produces the cells whose centers are contained by the
+ * polygon.
+ *
+ * @param geoPolygon The geofence and holes defining the area, in radians.
+ * @param res The resolution of the cells (0-15).
+ * @return The cells covering the polygon. ErrDomain is returned for non
+ *         finite vertices and when the polygon is too large to fill at the
+ *         resolution.
+ */
+func PolygonToCells(geoPolygon GeoPolygon, res int) ([]H3Index, error) {
+	if err := _validatePolygonToCells(&geoPolygon, res); err != nil || geoPolygon.geofence.IsZero() {
+		return nil, err
+	}
+
+	size := maxPolyfillSize(&geoPolygon, res)
+	if size > MAX_SLICE_CELLS {
+		return nil, ErrDomain
+	}
+	out := make([]H3Index, size)
+	if _polyfillInternal(&geoPolygon, res, out, nil) != 0 {
+		return nil, ErrFailed
+	}
+
+	n := 0
+	for i := range out {
+		if out[i] != H3_INVALID_INDEX {
+			out[n] = out[i]
+			n++
+		}
+	}
+	return out[:n], nil
+}
+
 /**
  * _getEdgeHexagons takes a given geofence ring (either the main geofence or
  * one of the holes) and traces it with hexagons and updates the search and
@@ -688,11 +931,13 @@ func _getEdgeHexagons(geofence *Geofence, numHexagons int, res int, numSearchHex
  * @param geoPolygon The geofence and holes defining the relevant area
  * @param res The Hexagon resolution (0-15)
  * @param out The slab of zeroed memory to write to. Assumed to be big enough.
+ * @param budget Checked for each hexagon searched, with the number of
+ *               hexagons found so far, or nil.
  *
  * @return An error code if any of the hash operations fails to insert a hexagon
- *         into an array of memory.
+ *         into an array of memory, or -2 when the budget ran out.
  */
-func _polyfillInternal(geoPolygon *GeoPolygon, res int, out []H3Index) int {
+func _polyfillInternal(geoPolygon *GeoPolygon, res int, out []H3Index, budget *workBudget) int {
 	// One of the goals of the polyfill algorithm is that two adjacent polygons
 	// with zero overlap have zero overlapping hexagons. That the hexagons are
 	// uniquely assigned. There are a few approaches to take here, such as
@@ -724,6 +969,7 @@ func _polyfillInternal(geoPolygon *GeoPolygon, res int, out []H3Index) int {
 	// blocks
 	numSearchHexes := 0
 	numFoundHexes := 0
+	numOutHexes := 0
 
 	// 1. Trace the hexagons along the polygon defining the outer geofence and
 	// add them to the search hash. The hexagon containing the geofence point
@@ -779,6 +1025,9 @@ func _polyfillInternal(geoPolygon *GeoPolygon, res int, out []H3Index) int {
 		currentSearchNum := 0
 		i := 0
 		for currentSearchNum < numSearchHexes {
+			if !budget.check(numOutHexes) {
+				return -2
+			}
 			ring := make([]H3Index, MAX_ONE_RING_SIZE)
 			searchHex := search[i]
 			kRing(searchHex, 1, ring)
@@ -829,6 +1078,7 @@ func _polyfillInternal(geoPolygon *GeoPolygon, res int, out []H3Index) int {
 
 				// Otherwise set it in the output array
 				out[loc] = hex
+				numOutHexes++
 
 				// Set the hexagon in the found hash
 				found[numFoundHexes] = hex
@@ -854,6 +1104,9 @@ func _polyfillInternal(geoPolygon *GeoPolygon, res int, out []H3Index) int {
 	search = nil
 	found = nil
 	bboxes = nil
+	if !budget.check(numOutHexes) {
+		return -2
+	}
 	return 0
 }
 
@@ -953,21 +1206,100 @@ func _vertexGraphToLinkedGeo(graph *VertexGraph, out *LinkedGeoPolygon) {
  * populated linked geo structure, or the memory for that structure will
  * not be freed.
  *
- * It is expected that all hexagons in the set have the same resolution and
- * that the set contains no duplicates. Behavior is undefined if duplicates
- * or multiple resolutions are present, and the algorithm may produce
- * unexpected or invalid output.
+ * The edges of the outline are matched by the cells meeting at their
+ * vertexes rather than by their coordinates, so the loops are exact at all
+ * resolutions and near the poles. Duplicates are ignored.
+ *
+ * It is expected that all hexagons in the set have the same resolution.
+ * Behavior is undefined if multiple resolutions are present, and the
+ * algorithm may produce unexpected or invalid output.
  *
  * @param h3Set    Set of hexagons
  * @param numHexes Number of hexagons in set
  * @param out      Output polygon
  */
 func h3SetToLinkedGeo(h3Set []H3Index, numHexes int, out *LinkedGeoPolygon) {
-	var graph VertexGraph
-	h3SetToVertexGraph(h3Set, numHexes, &graph)
-	_vertexGraphToLinkedGeo(&graph, out)
+	var graph outlineGraph
+	_h3SetToOutlineGraph(h3Set[:numHexes], &graph, nil)
+	_outlineGraphToLinkedGeo(&graph, out)
 	// TODO: The return value, possibly indicating an error, is discarded here -
 	// we should use this when we update the API to return a value
 	normalizeMultiPolygon(out)
-	destroyVertexGraph(&graph)
+}
+
+/**
+ * CellsToMultiPolygon produces the outlines of a set of cells, following
+ * GeoJSON MultiPolygon order: each polygon has an outer loop followed by its
+ * holes. Compacted sets are outlined by CompactCellsToMultiPolygon.
+ *
+ * @param h3Set Cells of a single resolution, without duplicates.
+ * @return The outlines. ErrFailed is returned when a hole could not be
+ *         assigned to an outer loop, as happens for loops around a pole.
+ */
+func CellsToMultiPolygon(h3Set []H3Index) (GeoMultiPolygon, error) {
+	if err := _validateCellsToMultiPolygon(h3Set); err != nil {
+		return GeoMultiPolygon{}, err
+	}
+
+	var graph outlineGraph
+	_h3SetToOutlineGraph(h3Set, &graph, nil)
+	multiPolygon, result := _geofencesToMultiPolygon(_outlineGraphToGeofences(&graph, nil), nil)
+	if result != NORMALIZATION_SUCCESS {
+		return GeoMultiPolygon{}, ErrFailed
+	}
+	return multiPolygon, nil
+}
+
+/**
+ * _validateCellsToMultiPolygon checks the input of an outline.
+ *
+ * @param h3Set The cells.
+ * @return ErrInvalidIndex, ErrResolutionMismatch or ErrDuplicateInput, or nil.
+ */
+func _validateCellsToMultiPolygon(h3Set []H3Index) error {
+	seen := make(map[H3Index]bool, len(h3Set))
+	for i := range h3Set {
+		if !h3IsValid(h3Set[i]) {
+			return ErrInvalidIndex
+		}
+		if H3_GET_RESOLUTION(h3Set[i]) != H3_GET_RESOLUTION(h3Set[0]) {
+			return ErrResolutionMismatch
+		}
+		if seen[h3Set[i]] {
+			return ErrDuplicateInput
+		}
+		seen[h3Set[i]] = true
+	}
+	return nil
+}
+
+/**
+ * CompactCellsToMultiPolygon produces the outlines of the area covered by a
+ * set of cells of mixed resolutions, such as the output of CompactCells. The
+ * outlines follow the cells of the finest resolution of the set, as those of
+ * the uncompacted set would, but only the cells along them are expanded.
+ *
+ * @param h3Set Cells of any resolution, duplicates and nested cells allowed.
+ * @return The outlines, or ErrInvalidIndex if a cell is not valid. ErrFailed
+ *         is returned when a hole could not be assigned to an outer loop.
+ */
+func CompactCellsToMultiPolygon(h3Set []H3Index) (GeoMultiPolygon, error) {
+	set, err := NewCellSet(h3Set...)
+	if err != nil {
+		return GeoMultiPolygon{}, err
+	}
+	res := 0
+	for _, h := range h3Set {
+		if H3_GET_RESOLUTION(h) > res {
+			res = H3_GET_RESOLUTION(h)
+		}
+	}
+
+	var graph outlineGraph
+	_cellSetToOutlineGraph(set, res, &graph)
+	multiPolygon, result := _geofencesToMultiPolygon(_outlineGraphToGeofences(&graph, nil), nil)
+	if result != NORMALIZATION_SUCCESS {
+		return GeoMultiPolygon{}, ErrFailed
+	}
+	return multiPolygon, nil
 }
@@ -1,6 +1,9 @@
 package h3
 
 import (
+	"fmt"
+	"math"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -145,15 +148,18 @@ func Test_h3SetToLinkedGeo(t *testing.T) {
 
 		h3SetToLinkedGeo(set, numHexes, &polygon)
 
-		// Note that the polygon order here is arbitrary, making this test
-		// somewhat brittle, but it's difficult to assert correctness otherwise
+		// The polygon order here is arbitrary, so find the big polygon first
 		require.True(t, countLinkedPolygons(&polygon) == 2, "Polygon count correct")
-		require.True(t, countLinkedLoops(&polygon) == 2, "Loop count on first polygon correct")
-		require.True(t, countLinkedCoords(polygon.first) == 42, "Got expected big outer loop")
-		require.True(t, countLinkedCoords(polygon.first.next) == 30, "Got expected big inner loop")
-		require.True(t, countLinkedLoops(polygon.next) == 2, "Loop count on second polygon correct")
-		require.True(t, countLinkedCoords(polygon.next.first) == 18, "Got expected outer loop")
-		require.True(t, countLinkedCoords(polygon.next.first.next) == 6, "Got expected inner loop")
+		big, small := &polygon, polygon.next
+		if countLinkedCoords(big.first) < countLinkedCoords(small.first) {
+			big, small = small, big
+		}
+		require.True(t, countLinkedLoops(big) == 2, "Loop count on big polygon correct")
+		require.True(t, countLinkedCoords(big.first) == 42, "Got expected big outer loop")
+		require.True(t, countLinkedCoords(big.first.next) == 30, "Got expected big inner loop")
+		require.True(t, countLinkedLoops(small) == 2, "Loop count on small polygon correct")
+		require.True(t, countLinkedCoords(small.first) == 18, "Got expected outer loop")
+		require.True(t, countLinkedCoords(small.first.next) == 6, "Got expected inner loop")
 		destroyLinkedPolygon(&polygon)
 	})
 
@@ -295,3 +301,367 @@ func Test_h3SetToVertexGraph(t *testing.T) {
 		destroyVertexGraph(&graph)
 	})
 }
+
+func Test_maxGridDistance(t *testing.T) {
+	for res := 0; res <= 3; res++ {
+		var pentagon H3Index
+		setH3Index(&pentagon, res, 4, 0)
+		for _, origin := range []H3Index{pentagon, geoToH3(&GeoCoord{Lat: 0.3, Lon: 0.2}, res)} {
+			// the disk is clamped to the bound, and still covers the sphere
+			_, distances, err := GridDiskDistances(origin, maxGridDistance(res)+1)
+			require.NoError(t, err)
+			require.Len(t, distances, int(numHexagons(res)), "res %d", res)
+			diameter := distances[len(distances)-1]
+			require.True(t, 3*diameter < maxGridDistance(res), "res %d diameter %d", res, diameter)
+			require.True(t, maxGridDistance(res) < 5*diameter, "res %d diameter %d", res, diameter)
+		}
+	}
+}
+
+func TestGridDisk(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		_, err := GridDisk(H3_INVALID_INDEX, 1)
+		require.Equal(t, ErrInvalidIndex, err)
+
+		_, err = GridDisk(0x8928308280fffff, -1)
+		require.Equal(t, ErrDomain, err)
+
+	})
+
+	t.Run("matchesHexRange", func(t *testing.T) {
+		origin := H3Index(0x8928308280fffff)
+		k := 5
+		expected := make([]H3Index, maxKringSize(k))
+		expectedDistances := make([]int, maxKringSize(k))
+		require.Equal(t, HEX_RANGE_SUCCESS, hexRangeDistances(origin, k, expected, expectedDistances))
+
+		out := make([]H3Index, maxKringSize(k))
+		distances := make([]int, maxKringSize(k))
+		n := _gridDiskDistancesBfs(origin, k, out, distances, nil)
+		require.Equal(t, maxKringSize(k), n)
+		require.ElementsMatch(t, expected, out)
+
+		expectedDist := map[H3Index]int{}
+		for i := range expected {
+			expectedDist[expected[i]] = expectedDistances[i]
+		}
+		for i := range out {
+			require.Equal(t, expectedDist[out[i]], distances[i], "distance of %x", out[i])
+		}
+	})
+
+	t.Run("fastPath", func(t *testing.T) {
+		// away from pentagons the cells come from hexRange, in its order
+		origin := H3Index(0x8928308280fffff)
+		k := 5
+		expected := make([]H3Index, maxKringSize(k))
+		expectedDistances := make([]int, maxKringSize(k))
+		require.Equal(t, HEX_RANGE_SUCCESS, hexRangeDistances(origin, k, expected, expectedDistances))
+
+		cells, distances, err := GridDiskDistances(origin, k)
+		require.NoError(t, err)
+		require.Equal(t, expected, cells)
+		require.Equal(t, expectedDistances, distances)
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		for res := 0; res <= MAX_H3_RES; res++ {
+			pentagons := make([]H3Index, 0)
+			getPentagonIndexes(res, &pentagons)
+
+			k := 3
+			if res < 2 {
+				// stay clear of the neighboring pentagons
+				k = res
+			}
+			for _, pentagon := range pentagons {
+				cells, distances, err := GridDiskDistances(pentagon, k)
+				require.NoError(t, err)
+				// ring r around a pentagon holds 5r cells
+				require.Len(t, cells, 1+5*k*(k+1)/2, "pentagon %x", pentagon)
+
+				seen := map[H3Index]bool{}
+				for i, cell := range cells {
+					require.True(t, h3IsValid(cell), "valid cell %x", cell)
+					require.False(t, seen[cell], "no duplicate %x", cell)
+					seen[cell] = true
+					if i > 0 {
+						require.True(t, distances[i] >= distances[i-1], "ordered by distance")
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("pentagonDistortion", func(t *testing.T) {
+		// kRing from a neighbor of a pentagon has to fall back to the
+		// breadth first search and must not lose its result
+		var pentagon H3Index
+		setH3Index(&pentagon, 5, 4, 0)
+		rotations := 0
+		origin := h3NeighborRotations(pentagon, J_AXES_DIGIT, &rotations)
+
+		k := 2
+		require.NotEqual(t, HEX_RANGE_SUCCESS, hexRange(origin, k, make([]H3Index, maxKringSize(k))))
+
+		out := make([]H3Index, maxKringSize(k))
+		kRing(origin, k, out)
+
+		expected := make([]H3Index, maxKringSize(k))
+		n := _gridDiskDistancesBfs(origin, k, expected, nil, nil)
+
+		found := make([]H3Index, 0)
+		for _, cell := range out {
+			if cell != H3_INVALID_INDEX {
+				found = append(found, cell)
+			}
+		}
+		require.ElementsMatch(t, expected[:n], found)
+		require.Contains(t, found, pentagon)
+	})
+
+	t.Run("largeAroundPentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 9, 4, 0)
+		cells, err := GridDisk(pentagon, 100)
+		require.NoError(t, err)
+		require.Len(t, cells, 1+5*100*101/2)
+	})
+
+	t.Run("wholeSphere", func(t *testing.T) {
+		res0 := make([]H3Index, res0IndexCount())
+		getRes0Indexes(res0)
+
+		cells, err := GridDisk(res0[0], 100)
+		require.NoError(t, err)
+		require.ElementsMatch(t, res0, cells)
+
+		cells, err = GridDisk(res0[0], math.MaxInt32)
+		require.NoError(t, err)
+		require.ElementsMatch(t, res0, cells)
+	})
+
+	t.Run("tooLarge", func(t *testing.T) {
+		// the whole sphere at res 15, more cells than a slice holds
+		_, err := GridDisk(0x8f2830828052d25, math.MaxInt32)
+		require.Equal(t, ErrDomain, err)
+	})
+}
+
+func TestGridRing(t *testing.T) {
+	t.Run("hexagon", func(t *testing.T) {
+		ring, err := GridRing(0x8928308280fffff, 2)
+		require.NoError(t, err)
+		require.Len(t, ring, 12)
+		for _, h := range ring {
+			d, _, err := GridDistance(0x8928308280fffff, h)
+			require.NoError(t, err)
+			require.Equal(t, 2, d)
+		}
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 5, 4, 0)
+		ring, err := GridRing(pentagon, 1)
+		require.NoError(t, err)
+		require.Len(t, ring, 5)
+
+		ring, err = GridRing(pentagon, 0)
+		require.NoError(t, err)
+		require.Equal(t, []H3Index{pentagon}, ring)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := GridRing(H3_INVALID_INDEX, 1)
+		require.Equal(t, ErrInvalidIndex, err)
+		_, err = GridRing(0x8928308280fffff, -1)
+		require.Equal(t, ErrDomain, err)
+	})
+
+	t.Run("beyondSphere", func(t *testing.T) {
+		ring, err := GridRing(0x8928308280fffff, math.MaxInt32)
+		require.NoError(t, err)
+		require.Empty(t, ring)
+	})
+}
+
+func TestPolygonToCells(t *testing.T) {
+	disk, err := GridDisk(0x8928308280fffff, 2)
+	require.NoError(t, err)
+
+	multiPolygon, err := CellsToMultiPolygon(disk)
+	require.NoError(t, err)
+	require.Len(t, multiPolygon.Polygons(), 1)
+	polygon := multiPolygon.Polygons()[0]
+	require.Len(t, polygon.Holes(), 0)
+	require.Len(t, polygon.Geofence().Verts(), 30)
+
+	cells, err := PolygonToCells(polygon, 9)
+	require.NoError(t, err)
+	require.ElementsMatch(t, disk, cells)
+
+	_, err = PolygonToCells(polygon, 16)
+	require.Equal(t, ErrDomain, err)
+	_, err = PolygonToCells(NewGeoPolygon(NewGeofence([]GeoCoord{{0, 0}, {math.NaN(), 0}, {0, 1}})), 5)
+	require.Equal(t, ErrDomain, err)
+	_, err = PolygonToCells(NewGeoPolygon(NewGeofence([]GeoCoord{{-1, -1.5}, {1, -1.5}, {1, 1.5}, {-1, 1.5}})), 15)
+	require.Equal(t, ErrDomain, err, "more cells than a slice holds")
+	_, err = CellsToMultiPolygon(append(disk, disk[0]))
+	require.Equal(t, ErrDuplicateInput, err)
+	_, err = CellsToMultiPolygon([]H3Index{disk[0], 0x85283473fffffff})
+	require.Equal(t, ErrResolutionMismatch, err)
+
+	// the hole of a ring around the pole is not found inside its outer loop
+	// in latitude and longitude, and is not silently dropped
+	pole := geoToH3(&GeoCoord{Lat: M_PI_2, Lon: 0}, 5)
+	disk, err = GridDisk(pole, 3)
+	require.NoError(t, err)
+	ring, err := GridRing(pole, 1)
+	require.NoError(t, err)
+	_, err = CellsToMultiPolygon(subtractCells(disk, ring))
+	require.Equal(t, ErrFailed, err)
+	_, err = CompactCellsToMultiPolygon(subtractCells(disk, ring))
+	require.Equal(t, ErrFailed, err)
+}
+
+func TestCompactCellsToMultiPolygon(t *testing.T) {
+	// the loop and hole counts of the polygons, and all their vertices
+	requireSameOutline := func(t *testing.T, expected GeoMultiPolygon, actual GeoMultiPolygon) {
+		shape := func(m GeoMultiPolygon) ([]int, []GeoCoord) {
+			var holes []int
+			var verts []GeoCoord
+			for _, polygon := range m.Polygons() {
+				holes = append(holes, len(polygon.Holes()))
+				verts = append(verts, polygon.Geofence().Verts()...)
+				for _, hole := range polygon.Holes() {
+					verts = append(verts, hole.Verts()...)
+				}
+			}
+			sort.Ints(holes)
+			sort.Slice(verts, func(i, j int) bool {
+				if verts[i].Lat != verts[j].Lat {
+					return verts[i].Lat < verts[j].Lat
+				}
+				return verts[i].Lon < verts[j].Lon
+			})
+			return holes, verts
+		}
+		expectedHoles, expectedVerts := shape(expected)
+		actualHoles, actualVerts := shape(actual)
+		require.Equal(t, expectedHoles, actualHoles)
+		require.Equal(t, expectedVerts, actualVerts)
+	}
+
+	var pentagon H3Index
+	setH3Index(&pentagon, 3, 4, 0)
+	for _, origin := range []H3Index{0x85283473fffffff, pentagon} {
+		// the origin in a ring, next to a few cells of the finest resolution
+		disk, err := GridDisk(origin, 3)
+		require.NoError(t, err)
+		ring, err := GridRing(origin, 1)
+		require.NoError(t, err)
+		cells, err := UncompactCells(subtractCells(disk, ring), H3_GET_RESOLUTION(origin)+2)
+		require.NoError(t, err)
+		outer, err := GridRing(origin, 6)
+		require.NoError(t, err)
+		fine, err := UncompactCells(outer[:2], H3_GET_RESOLUTION(origin)+2)
+		require.NoError(t, err)
+		cells = append(cells, fine[:6]...)
+
+		expected, err := CellsToMultiPolygon(cells)
+		require.NoError(t, err)
+		require.Len(t, expected.Polygons(), 3)
+
+		compacted, err := CompactCells(cells)
+		require.NoError(t, err)
+		require.True(t, len(compacted) < len(cells))
+		actual, err := CompactCellsToMultiPolygon(compacted)
+		require.NoError(t, err)
+		requireSameOutline(t, expected, actual)
+
+		// duplicated and nested cells
+		actual, err = CompactCellsToMultiPolygon(append(append(compacted, cells...), compacted[0]))
+		require.NoError(t, err)
+		requireSameOutline(t, expected, actual)
+	}
+
+	t.Run("coarse", func(t *testing.T) {
+		// a single fine cell makes the outline of a coarse cell follow its
+		// 7^6 descendants, of which only those along the outline are expanded
+		multiPolygon, err := CompactCellsToMultiPolygon([]H3Index{0x85283473fffffff, h3ToParent(0x8f2830828052d25, 11)})
+		require.NoError(t, err)
+		require.Len(t, multiPolygon.Polygons(), 2)
+		for _, polygon := range multiPolygon.Polygons() {
+			require.Empty(t, polygon.Holes())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		multiPolygon, err := CompactCellsToMultiPolygon(nil)
+		require.NoError(t, err)
+		require.Empty(t, multiPolygon.Polygons())
+		_, err = CompactCellsToMultiPolygon([]H3Index{0x85283473fffffff, H3_INVALID_INDEX})
+		require.Equal(t, ErrInvalidIndex, err)
+	})
+}
+
+func BenchmarkCellsToMultiPolygon(b *testing.B) {
+	for _, res := range []int{8, 9, 10} {
+		cells, err := PolygonToCells(NewGeoPolygon(NewGeofence(sfVerts)), res)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("res%d/vertexGraph", res), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var graph VertexGraph
+				var polygon LinkedGeoPolygon
+				h3SetToVertexGraph(cells, len(cells), &graph)
+				_vertexGraphToLinkedGeo(&graph, &polygon)
+				normalizeMultiPolygon(&polygon)
+			}
+		})
+		b.Run(fmt.Sprintf("res%d/outline", res), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := CellsToMultiPolygon(cells); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGridDisk(b *testing.B) {
+	var pentagon H3Index
+	setH3Index(&pentagon, 9, 4, 0)
+
+	for _, origin := range []struct {
+		name string
+		h    H3Index
+	}{
+		{"hexagon", 0x8928308280fffff},
+		{"pentagon", pentagon},
+	} {
+		for _, k := range []int{2, 20} {
+			h := origin.h
+			k := k
+			b.Run(fmt.Sprintf("%s/k%d/kRing", origin.name, k), func(b *testing.B) {
+				b.ReportAllocs()
+				out := make([]H3Index, maxKringSize(k))
+				for i := 0; i < b.N; i++ {
+					kRing(h, k, out)
+				}
+			})
+			b.Run(fmt.Sprintf("%s/k%d/GridDisk", origin.name, k), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := GridDisk(h, k); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
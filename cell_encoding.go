@@ -0,0 +1,247 @@
+package h3
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+/** Version of the binary cell encoding written by MarshalCells. */
+const CELL_ENCODING_VERSION = 1
+
+/*
+ * The encoding starts with the version byte, followed by one group per
+ * resolution present, in ascending order:
+ *
+ *     uvarint resolution
+ *     uvarint number of cells
+ *     uvarint first key, then uvarint key deltas
+ *
+ * The key of a cell is the index shifted right past its unused digits,
+ * which are all 7. Cells are sorted, so the deltas are small positive
+ * numbers for dense sets and mostly fit in a single byte.
+ */
+
+/**
+ * _unusedDigitBits returns the number of bits of the unused digits of cells
+ * of the resolution.
+ */
+func _unusedDigitBits(res int) uint {
+	return uint((MAX_H3_RES - res) * H3_PER_DIGIT_OFFSET)
+}
+
+/**
+ * MarshalCells encodes a set of cells compactly. The cells are normalized as
+ * by NewCellSet first, so that complete sets of siblings take the space of
+ * their parent; decoding yields the normalized cells.
+ *
+ * @param cells Cells of any resolution.
+ * @return The encoded cells, or ErrInvalidIndex if a cell is not valid.
+ */
+func MarshalCells(cells []H3Index) ([]byte, error) {
+	s, err := NewCellSet(cells...)
+	if err != nil {
+		return nil, err
+	}
+	return s.MarshalBinary()
+}
+
+/**
+ * MarshalBinary encodes the cells of the set as MarshalCells does.
+ */
+func (s *CellSet) MarshalBinary() ([]byte, error) {
+	cells := s.Cells()
+	out := make([]byte, 0, 1+len(cells)+2*binary.MaxVarintLen64)
+	out = append(out, CELL_ENCODING_VERSION)
+
+	var buf [binary.MaxVarintLen64]byte
+	putUvarint := func(v uint64) {
+		n := binary.PutUvarint(buf[:], v)
+		out = append(out, buf[:n]...)
+	}
+
+	for start := 0; start < len(cells); {
+		res := H3_GET_RESOLUTION(cells[start])
+		end := start
+		for end < len(cells) && H3_GET_RESOLUTION(cells[end]) == res {
+			end++
+		}
+
+		putUvarint(uint64(res))
+		putUvarint(uint64(end - start))
+		prev := uint64(0)
+		for _, h := range cells[start:end] {
+			key := uint64(h) >> _unusedDigitBits(res)
+			putUvarint(key - prev)
+			prev = key
+		}
+		start = end
+	}
+	return out, nil
+}
+
+/**
+ * UnmarshalBinary replaces the cells of the set by the decoded cells.
+ */
+func (s *CellSet) UnmarshalBinary(data []byte) error {
+	cells, err := UnmarshalCells(data)
+	if err != nil {
+		return err
+	}
+	decoded, err := NewCellSet(cells...)
+	if err != nil {
+		return err // LCOV_EXCL_LINE
+	}
+	*s = *decoded
+	return nil
+}
+
+/**
+ * UnmarshalCells decodes all the cells encoded by MarshalCells.
+ *
+ * @param data The encoded cells.
+ * @return The cells in ascending order, or ErrInvalidEncoding.
+ */
+func UnmarshalCells(data []byte) ([]H3Index, error) {
+	d := NewCellDecoder(bytes.NewReader(data))
+	out := make([]H3Index, 0)
+	for d.Next() {
+		out = append(out, d.Cell())
+	}
+	if d.Err() != nil {
+		return nil, d.Err()
+	}
+	return out, nil
+}
+
+/**
+ * @brief Lazy decoder of cells encoded by MarshalCells
+ *
+ * Cells are read one at a time, as with bufio.Scanner:
+ *
+ *     d := NewCellDecoder(r)
+ *     for d.Next() {
+ *         use(d.Cell())
+ *     }
+ *     if err := d.Err(); err != nil {
+ *         ...
+ *     }
+ */
+type CellDecoder struct {
+	r         *_byteReader
+	started   bool
+	res       int    ///< resolution of the current group, -1 before the first
+	remaining uint64 ///< cells left in the current group
+	first     bool   ///< whether the next cell is the first of its group
+	key       uint64 ///< key of the last cell
+	cell      H3Index
+	err       error
+}
+
+/**
+ * NewCellDecoder creates a decoder reading from r. Readers which are not
+ * io.ByteReaders are buffered.
+ */
+func NewCellDecoder(r io.Reader) *CellDecoder {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &CellDecoder{r: &_byteReader{r: br}, res: -1}
+}
+
+/**
+ * _byteReader remembers read errors, to tell them apart from malformed
+ * varints.
+ */
+type _byteReader struct {
+	r   io.ByteReader
+	err error
+}
+
+func (b *_byteReader) ReadByte() (byte, error) {
+	c, err := b.r.ReadByte()
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return c, err
+}
+
+/**
+ * Next decodes the next cell.
+ *
+ * @return Whether there is a cell; false at the end of the input or on error.
+ */
+func (d *CellDecoder) Next() bool {
+	if d.err != nil {
+		return false
+	}
+
+	if !d.started {
+		d.started = true
+		version, err := d.r.ReadByte()
+		if err != nil || version != CELL_ENCODING_VERSION {
+			return d.fail()
+		}
+	}
+
+	for d.remaining == 0 {
+		res, err := binary.ReadUvarint(d.r)
+		if err == io.EOF {
+			return false
+		}
+		if err != nil || res > MAX_H3_RES || int(res) <= d.res {
+			return d.fail()
+		}
+		n, err := binary.ReadUvarint(d.r)
+		if err != nil || n == 0 {
+			return d.fail()
+		}
+		d.res, d.remaining, d.first, d.key = int(res), n, true, 0
+	}
+
+	delta, err := binary.ReadUvarint(d.r)
+	if err != nil || (delta == 0 && !d.first) {
+		return d.fail()
+	}
+	bits := _unusedDigitBits(d.res)
+	key := d.key + delta
+	if key < d.key || key>>(64-bits) != 0 {
+		return d.fail()
+	}
+
+	cell := H3Index(key<<bits | (1<<bits - 1))
+	if !h3IsValid(cell) || H3_GET_RESOLUTION(cell) != d.res {
+		return d.fail()
+	}
+	d.key, d.cell, d.first = key, cell, false
+	d.remaining--
+	return true
+}
+
+/**
+ * fail records the decoding error. Errors of the reader are passed on as
+ * they are, everything else is ErrInvalidEncoding.
+ */
+func (d *CellDecoder) fail() bool {
+	d.err = d.r.err
+	if d.err == nil {
+		d.err = ErrInvalidEncoding
+	}
+	return false
+}
+
+/**
+ * Cell returns the cell decoded by the last call to Next.
+ */
+func (d *CellDecoder) Cell() H3Index {
+	return d.cell
+}
+
+/**
+ * Err returns the first error met by Next, nil at a clean end of the input.
+ */
+func (d *CellDecoder) Err() error {
+	return d.err
+}
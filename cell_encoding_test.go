@@ -0,0 +1,106 @@
+package h3
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalCells(t *testing.T) {
+	origin := geoToH3(GeoFromWGS84(37.77, -122.42), 10)
+	disk, err := GridDisk(origin, 60)
+	require.NoError(t, err)
+
+	t.Run("roundTrip", func(t *testing.T) {
+		data, err := MarshalCells(disk)
+		require.NoError(t, err)
+		require.True(t, len(data) < len(disk), "%d bytes for %d cells", len(data), len(disk))
+
+		cells, err := UnmarshalCells(data)
+		require.NoError(t, err)
+		s, err := NewCellSet(disk...)
+		require.NoError(t, err)
+		require.Equal(t, s.Cells(), cells)
+
+		uncompacted, err := UncompactCells(cells, 10)
+		require.NoError(t, err)
+		expected := append([]H3Index{}, disk...)
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		sort.Slice(uncompacted, func(i, j int) bool { return uncompacted[i] < uncompacted[j] })
+		require.Equal(t, expected, uncompacted)
+	})
+
+	t.Run("mixedResolutions", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 0, 4, 0)
+		cells := []H3Index{0x8928308280fffff, 0x85283473fffffff, pentagon, geoToH3(GeoFromWGS84(35.68, 139.76), 15)}
+
+		data, err := MarshalCells(cells)
+		require.NoError(t, err)
+		decoded, err := UnmarshalCells(data)
+		require.NoError(t, err)
+		require.ElementsMatch(t, cells, decoded)
+
+		var s CellSet
+		require.NoError(t, s.UnmarshalBinary(data))
+		require.Equal(t, 4, s.Len())
+		again, err := s.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, data, again)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		data, err := MarshalCells(nil)
+		require.NoError(t, err)
+		require.Equal(t, []byte{CELL_ENCODING_VERSION}, data)
+
+		cells, err := UnmarshalCells(data)
+		require.NoError(t, err)
+		require.Empty(t, cells)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := MarshalCells([]H3Index{H3_INVALID_INDEX})
+		require.Equal(t, ErrInvalidIndex, err)
+
+		data, err := MarshalCells(disk)
+		require.NoError(t, err)
+
+		for _, corrupt := range [][]byte{
+			nil,
+			{CELL_ENCODING_VERSION + 1},
+			data[:len(data)-1],
+			append(append([]byte{}, data...), 0),
+			{CELL_ENCODING_VERSION, 16, 1, 1},
+			{CELL_ENCODING_VERSION, 5, 0},
+			{CELL_ENCODING_VERSION, 0, 1, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01},
+		} {
+			_, err := UnmarshalCells(corrupt)
+			require.Equal(t, ErrInvalidEncoding, err, "%v", corrupt)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		data, err := MarshalCells(disk)
+		require.NoError(t, err)
+
+		d := NewCellDecoder(iotest.OneByteReader(bytes.NewReader(data)))
+		n := 0
+		for d.Next() {
+			require.True(t, h3IsValid(d.Cell()))
+			n++
+		}
+		require.NoError(t, d.Err())
+		require.True(t, n > 0)
+
+		d = NewCellDecoder(iotest.TimeoutReader(iotest.HalfReader(bytes.NewReader(data))))
+		for d.Next() {
+		}
+		require.True(t, errors.Is(d.Err(), iotest.ErrTimeout))
+		require.False(t, d.Next())
+	})
+}
@@ -0,0 +1,263 @@
+package h3
+
+import "sort"
+
+/**
+ * @brief Set of cells of mixed resolutions
+ *
+ * A CellSet describes the area covered by its cells. It is kept normalized:
+ * no cell is stored together with one of its ancestors, and complete sets of
+ * siblings are replaced by their parent, as compact does. Equal areas
+ * therefore always hold the same cells, and set operations between covers
+ * of different resolutions are exact.
+ *
+ * The cells are kept in a CellTrie, so that the cells covering or covered
+ * by a cell are found along its digit path instead of by a scan of the set.
+ *
+ * The zero value is an empty set ready to use.
+ */
+type CellSet struct {
+	trie CellTrie
+}
+
+/**
+ * NewCellSet creates a set holding the given cells.
+ *
+ * @param cells Cells of any resolution, duplicates and nested cells allowed.
+ * @return The set, or ErrInvalidIndex if a cell is not valid.
+ */
+func NewCellSet(cells ...H3Index) (*CellSet, error) {
+	for _, h := range cells {
+		if !h3IsValid(h) {
+			return nil, ErrInvalidIndex
+		}
+	}
+
+	// Coarse cells first, so that adding a cell never has to look for
+	// descendants to replace
+	sorted := append([]H3Index{}, cells...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	s := &CellSet{}
+	for _, h := range sorted {
+		s._add(h)
+	}
+	return s, nil
+}
+
+/**
+ * Len returns the number of cells stored in the normalized set.
+ */
+func (s *CellSet) Len() int {
+	return s.trie.Len()
+}
+
+/**
+ * Add adds the area of a cell to the set. Adding a cell which is already
+ * covered does nothing; descendants of the cell stored in the set are
+ * replaced by it.
+ *
+ * @param h The cell to add.
+ * @return ErrInvalidIndex if the cell is not valid.
+ */
+func (s *CellSet) Add(h H3Index) error {
+	if !h3IsValid(h) {
+		return ErrInvalidIndex
+	}
+	s._add(h)
+	return nil
+}
+
+/**
+ * Contains returns whether the cell is covered by the set, that is whether
+ * the cell or one of its ancestors is in the set. Invalid cells are never
+ * contained.
+ *
+ * @param h The cell to look up.
+ */
+func (s *CellSet) Contains(h H3Index) bool {
+	if !h3IsValid(h) {
+		return false
+	}
+	_, ok := s._covering(h)
+	return ok
+}
+
+/**
+ * Cells returns the cells of the normalized set in ascending order.
+ */
+func (s *CellSet) Cells() []H3Index {
+	out := s.trie.Cells()
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
+
+/**
+ * Uncompact returns the cells of the set expanded to a single resolution.
+ *
+ * @param res The resolution to expand to.
+ * @return The cells at resolution res, as UncompactCells. The set must not
+ *         hold cells finer than res.
+ */
+func (s *CellSet) Uncompact(res int) ([]H3Index, error) {
+	return UncompactCells(s.Cells(), res)
+}
+
+/**
+ * Union returns the cells covered by either set.
+ */
+func (s *CellSet) Union(o *CellSet) *CellSet {
+	out := &CellSet{trie: s.trie._clone()}
+	for _, h := range o.trie.Cells() {
+		out._add(h)
+	}
+	return out
+}
+
+/**
+ * Intersect returns the cells covered by both sets. Two cells either nest or
+ * do not overlap at all, so the intersection consists of the cells of
+ * either set that are covered by the other one.
+ */
+func (s *CellSet) Intersect(o *CellSet) *CellSet {
+	out := &CellSet{}
+	for _, h := range s.trie.Cells() {
+		if _, ok := o._covering(h); ok {
+			out._add(h)
+		}
+	}
+	for _, h := range o.trie.Cells() {
+		if _, ok := s._covering(h); ok {
+			out._add(h)
+		}
+	}
+	return out
+}
+
+/**
+ * Difference returns the cells covered by s but not by o. Cells of s which
+ * contain cells of o are split into their children as far as needed.
+ */
+func (s *CellSet) Difference(o *CellSet) *CellSet {
+	out := &CellSet{}
+	ancestors := o._ancestors()
+	for _, h := range s.trie.Cells() {
+		_subtract(h, o, ancestors, out)
+	}
+	return out
+}
+
+/**
+ * SymmetricDifference returns the cells covered by exactly one of the sets.
+ */
+func (s *CellSet) SymmetricDifference(o *CellSet) *CellSet {
+	out := s.Difference(o)
+	for _, h := range o.Difference(s).trie.Cells() {
+		out._add(h)
+	}
+	return out
+}
+
+/**
+ * _covering finds the cell of the set which is h or an ancestor of h.
+ *
+ * @param h A valid cell.
+ * @return The covering cell, and whether there is one.
+ */
+func (s *CellSet) _covering(h H3Index) (H3Index, bool) {
+	node := s.trie.roots[H3_GET_BASE_CELL(h)]
+	for r := 0; node != nil; r++ {
+		if node.stored {
+			return h3ToParent(h, r), true
+		}
+		if r == H3_GET_RESOLUTION(h) {
+			break
+		}
+		node = node.children[H3_GET_INDEX_DIGIT(h, r+1)]
+	}
+	return H3_INVALID_INDEX, false
+}
+
+/**
+ * _ancestors returns the strict ancestors of all the cells of the set, which
+ * are the cells that partially overlap the set.
+ */
+func (s *CellSet) _ancestors() map[H3Index]struct{} {
+	ancestors := map[H3Index]struct{}{}
+	for _, h := range s.trie.Cells() {
+		for r := H3_GET_RESOLUTION(h) - 1; r >= 0; r-- {
+			parent := h3ToParent(h, r)
+			if _, ok := ancestors[parent]; ok {
+				// the coarser ancestors are in already
+				break
+			}
+			ancestors[parent] = struct{}{}
+		}
+	}
+	return ancestors
+}
+
+/**
+ * _add adds a valid cell, keeping the set normalized.
+ */
+func (s *CellSet) _add(h H3Index) {
+	if _, ok := s._covering(h); ok {
+		return
+	}
+
+	// The descendants of h are all in the subtree of its node
+	s.trie._prune(h)
+	s.trie._insert(h)
+
+	// Replace complete sets of siblings by their parent
+	for res := H3_GET_RESOLUTION(h); res > 0; res-- {
+		parent := h3ToParent(h, res-1)
+		for _, sibling := range _directChildren(parent) {
+			if node := s.trie._node(sibling); node == nil || !node.stored {
+				return
+			}
+		}
+		s.trie._prune(parent)
+		s.trie._insert(parent)
+		h = parent
+	}
+}
+
+/**
+ * _directChildren returns the children of a cell at the next resolution,
+ * without the deleted subsequence of pentagons.
+ */
+func _directChildren(h H3Index) []H3Index {
+	children := make([]H3Index, 0, 7)
+	h3ToChildren(h, H3_GET_RESOLUTION(h)+1, &children)
+
+	n := 0
+	for _, c := range children {
+		if c != H3_INVALID_INDEX {
+			children[n] = c
+			n++
+		}
+	}
+	return children[:n]
+}
+
+/**
+ * _subtract adds the part of the cell h which is not covered by o to out.
+ *
+ * @param h A valid cell.
+ * @param o The set to subtract.
+ * @param ancestors The strict ancestors of the cells of o.
+ * @param out The set to add the remainder to.
+ */
+func _subtract(h H3Index, o *CellSet, ancestors map[H3Index]struct{}, out *CellSet) {
+	if _, ok := o._covering(h); ok {
+		return
+	}
+	if _, ok := ancestors[h]; !ok {
+		out._add(h)
+		return
+	}
+	for _, c := range _directChildren(h) {
+		_subtract(c, o, ancestors, out)
+	}
+}
@@ -0,0 +1,184 @@
+package h3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCellSet(t *testing.T) {
+	parent := H3Index(0x85283473fffffff)
+	children := make([]H3Index, 0)
+	h3ToChildren(parent, 6, &children)
+
+	t.Run("add", func(t *testing.T) {
+		s := &CellSet{}
+		require.NoError(t, s.Add(children[0]))
+		require.NoError(t, s.Add(children[0]))
+		require.Equal(t, 1, s.Len())
+
+		grandchild := h3ToCenterChild(children[0], 9)
+		require.NoError(t, s.Add(grandchild))
+		require.Equal(t, []H3Index{children[0]}, s.Cells(), "covered by an ancestor")
+		require.True(t, s.Contains(grandchild))
+		require.True(t, s.Contains(children[0]))
+		require.False(t, s.Contains(parent))
+		require.False(t, s.Contains(children[1]))
+
+		require.NoError(t, s.Add(parent))
+		require.Equal(t, []H3Index{parent}, s.Cells(), "descendants replaced")
+
+		require.Equal(t, ErrInvalidIndex, s.Add(H3_INVALID_INDEX))
+		require.False(t, s.Contains(H3_INVALID_INDEX))
+	})
+
+	t.Run("compactsSiblings", func(t *testing.T) {
+		s, err := NewCellSet(children[1:]...)
+		require.NoError(t, err)
+		require.Equal(t, 6, s.Len())
+
+		require.NoError(t, s.Add(children[0]))
+		require.Equal(t, []H3Index{parent}, s.Cells())
+
+		var pentagon H3Index
+		setH3Index(&pentagon, 5, 4, 0)
+		cells, err := UncompactCells([]H3Index{pentagon}, 7)
+		require.NoError(t, err)
+		s, err = NewCellSet(cells...)
+		require.NoError(t, err)
+		require.Equal(t, []H3Index{pentagon}, s.Cells())
+	})
+
+	t.Run("intersect", func(t *testing.T) {
+		// a compacted cover intersected with a cover of finer cells yields
+		// the fine cells inside of it
+		country, err := NewCellSet(parent, 0x8928308280fffff)
+		require.NoError(t, err)
+
+		origin := h3ToCenterChild(children[3], 9)
+		disk, err := GridDisk(origin, 20)
+		require.NoError(t, err)
+		city, err := NewCellSet(disk...)
+		require.NoError(t, err)
+
+		expected := make([]H3Index, 0)
+		for _, h := range disk {
+			if h3ToParent(h, 5) == parent {
+				expected = append(expected, h)
+			}
+		}
+		require.True(t, len(expected) < len(disk), "city crosses the border")
+
+		overlap := country.Intersect(city)
+		cells, err := overlap.Uncompact(9)
+		require.NoError(t, err)
+		require.ElementsMatch(t, expected, cells)
+		require.Equal(t, overlap.Cells(), city.Intersect(country).Cells())
+	})
+
+	t.Run("difference", func(t *testing.T) {
+		removed := h3ToCenterChild(children[2], 7)
+		s, err := NewCellSet(parent)
+		require.NoError(t, err)
+		o, err := NewCellSet(removed, 0x8928308280fffff)
+		require.NoError(t, err)
+
+		d := s.Difference(o)
+		require.Equal(t, 6+6, d.Len())
+		require.False(t, d.Contains(removed))
+		require.True(t, d.Contains(children[3]))
+		cells, err := d.Uncompact(7)
+		require.NoError(t, err)
+		require.Len(t, cells, 48)
+
+		require.Equal(t, 0, o.Difference(o).Len())
+		require.Equal(t, []H3Index{0x8928308280fffff}, o.Difference(s).Cells())
+	})
+
+	t.Run("unionAndSymmetricDifference", func(t *testing.T) {
+		a, err := NewCellSet(children[:4]...)
+		require.NoError(t, err)
+		b, err := NewCellSet(children[3:]...)
+		require.NoError(t, err)
+
+		require.Equal(t, []H3Index{parent}, a.Union(b).Cells())
+		require.ElementsMatch(t, []H3Index{children[3]}, a.Intersect(b).Cells())
+
+		x := a.SymmetricDifference(b)
+		require.Equal(t, 6, x.Len())
+		require.False(t, x.Contains(children[3]))
+		require.Equal(t, x.Cells(), b.SymmetricDifference(a).Cells())
+		require.Equal(t, a.Union(b).Cells(), x.Union(a.Intersect(b)).Cells())
+	})
+
+	t.Run("mixedResolutions", func(t *testing.T) {
+		// the fine cells of a cover are added before the coarse cells
+		// replacing them, and the union is kept apart from its operands
+		disk, err := GridDisk(0x85283473fffffff, 2)
+		require.NoError(t, err)
+		fine, err := UncompactCells(disk, 8)
+		require.NoError(t, err)
+		a, err := NewCellSet(fine[:len(fine)/2]...)
+		require.NoError(t, err)
+		b, err := NewCellSet(disk...)
+		require.NoError(t, err)
+		n := a.Len()
+
+		u := a.Union(b)
+		require.ElementsMatch(t, b.Cells(), u.Cells())
+		require.Equal(t, n, a.Len())
+		require.Equal(t, a.Cells(), u.Intersect(a).Cells())
+
+		for _, h := range disk[:7] {
+			require.NoError(t, a.Add(h))
+		}
+		require.Equal(t, a.Cells(), a.Intersect(u).Cells())
+		require.Equal(t, 0, a.Difference(u).Len())
+	})
+
+	t.Run("zeroValue", func(t *testing.T) {
+		var s CellSet
+		require.Equal(t, 0, s.Len())
+		require.False(t, s.Contains(parent))
+		require.Empty(t, s.Cells())
+		require.Equal(t, 0, s.Union(&CellSet{}).Len())
+	})
+}
+
+func BenchmarkCellSetUnion(b *testing.B) {
+	disk, err := GridDisk(0x85283473fffffff, 3)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cells, err := UncompactCells(disk, 9)
+	if err != nil {
+		b.Fatal(err)
+	}
+	// every other fine cell, so that nothing compacts
+	fine := make([]H3Index, 0, len(cells)/2)
+	for i := 0; i < len(cells); i += 2 {
+		fine = append(fine, cells[i])
+	}
+	s, err := NewCellSet(fine...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	// every other cell of a coarser resolution, each replacing a part of s
+	coarse, err := UncompactCells(disk, 7)
+	if err != nil {
+		b.Fatal(err)
+	}
+	half := make([]H3Index, 0, len(coarse)/2)
+	for i := 0; i < len(coarse); i += 2 {
+		half = append(half, coarse[i])
+	}
+	o, err := NewCellSet(half...)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.Union(o)
+	}
+}
@@ -0,0 +1,295 @@
+package h3
+
+/**
+ * @brief Node of a CellTrie, standing for a single cell
+ */
+type cellTrieNode struct {
+	children [NUM_DIGITS]*cellTrieNode ///< nodes of the children, by digit
+	stored   bool                      ///< whether the cell itself was inserted
+	count    int                       ///< number of cells stored in the subtree
+}
+
+/**
+ * @brief Cells stored along their digit path
+ *
+ * H3 cells form 122 trees rooted at the base cells, each cell having up to
+ * seven children addressed by the digit of the next resolution. CellTrie
+ * stores cells of any resolution along these paths, which answers coverage
+ * and containment queries in time proportional to the resolution instead of
+ * the number of cells.
+ *
+ * Cells are stored as inserted: a cell and its descendants may be stored
+ * together.
+ *
+ * The zero value is an empty trie ready to use.
+ */
+type CellTrie struct {
+	roots  [NUM_BASE_CELLS]*cellTrieNode
+	maxRes int ///< finest resolution inserted
+}
+
+/**
+ * _node returns the node of a valid cell, or nil when nothing is stored at
+ * or under it.
+ */
+func (t *CellTrie) _node(h H3Index) *cellTrieNode {
+	node := t.roots[H3_GET_BASE_CELL(h)]
+	for r := 1; node != nil && r <= H3_GET_RESOLUTION(h); r++ {
+		node = node.children[H3_GET_INDEX_DIGIT(h, r)]
+	}
+	return node
+}
+
+/**
+ * Len returns the number of cells stored.
+ */
+func (t *CellTrie) Len() int {
+	n := 0
+	for _, root := range t.roots {
+		if root != nil {
+			n += root.count
+		}
+	}
+	return n
+}
+
+/**
+ * Insert stores a cell. Inserting a cell twice stores it once.
+ *
+ * @param h The cell to store.
+ * @return ErrInvalidIndex if the cell is not valid.
+ */
+func (t *CellTrie) Insert(h H3Index) error {
+	if !h3IsValid(h) {
+		return ErrInvalidIndex
+	}
+	t._insert(h)
+	return nil
+}
+
+/**
+ * _insert stores a valid cell.
+ */
+func (t *CellTrie) _insert(h H3Index) {
+	if node := t._node(h); node != nil && node.stored {
+		return
+	}
+
+	res := H3_GET_RESOLUTION(h)
+	if res > t.maxRes {
+		t.maxRes = res
+	}
+
+	slot := &t.roots[H3_GET_BASE_CELL(h)]
+	for r := 0; ; r++ {
+		if *slot == nil {
+			*slot = &cellTrieNode{}
+		}
+		(*slot).count++
+		if r == res {
+			(*slot).stored = true
+			return
+		}
+		slot = &(*slot).children[H3_GET_INDEX_DIGIT(h, r+1)]
+	}
+}
+
+/**
+ * _prune removes a valid cell and all the cells stored under it, dropping
+ * the nodes left empty.
+ */
+func (t *CellTrie) _prune(h H3Index) {
+	node := t._node(h)
+	if node == nil {
+		return
+	}
+
+	n := node.count
+	slot := &t.roots[H3_GET_BASE_CELL(h)]
+	for r := 0; ; r++ {
+		(*slot).count -= n
+		if (*slot).count == 0 {
+			*slot = nil
+			return
+		}
+		slot = &(*slot).children[H3_GET_INDEX_DIGIT(h, r+1)]
+	}
+}
+
+/**
+ * _clone returns a copy of the trie sharing no nodes with it.
+ */
+func (t *CellTrie) _clone() CellTrie {
+	out := CellTrie{maxRes: t.maxRes}
+	for baseCell, root := range t.roots {
+		out.roots[baseCell] = _cloneCellTrie(root)
+	}
+	return out
+}
+
+func _cloneCellTrie(node *cellTrieNode) *cellTrieNode {
+	if node == nil {
+		return nil
+	}
+	out := &cellTrieNode{stored: node.stored, count: node.count}
+	for digit, child := range node.children {
+		out.children[digit] = _cloneCellTrie(child)
+	}
+	return out
+}
+
+/**
+ * Contains returns whether the cell itself is stored.
+ *
+ * @param h The cell to look up.
+ */
+func (t *CellTrie) Contains(h H3Index) bool {
+	if !h3IsValid(h) {
+		return false
+	}
+	node := t._node(h)
+	return node != nil && node.stored
+}
+
+/**
+ * Covering returns the finest stored cell which is h or an ancestor of h.
+ *
+ * @param h The cell to look up.
+ * @return The covering cell, and whether there is one. Invalid cells are
+ *         never covered.
+ */
+func (t *CellTrie) Covering(h H3Index) (H3Index, bool) {
+	if !h3IsValid(h) {
+		return H3_INVALID_INDEX, false
+	}
+
+	covering, found := H3_INVALID_INDEX, false
+	node := t.roots[H3_GET_BASE_CELL(h)]
+	for r := 0; node != nil; r++ {
+		if node.stored {
+			covering, found = h3ToParent(h, r), true
+		}
+		if r == H3_GET_RESOLUTION(h) {
+			break
+		}
+		node = node.children[H3_GET_INDEX_DIGIT(h, r+1)]
+	}
+	return covering, found
+}
+
+/**
+ * Covers returns whether h or one of its ancestors is stored.
+ *
+ * @param h The cell to look up.
+ */
+func (t *CellTrie) Covers(h H3Index) bool {
+	_, found := t.Covering(h)
+	return found
+}
+
+/**
+ * CoversPoint returns whether the cell containing a location is covered,
+ * at the finest resolution stored.
+ *
+ * @param g The location in radians.
+ * @return Whether the location is covered, or ErrDomain for non finite
+ *         coordinates.
+ */
+func (t *CellTrie) CoversPoint(g GeoCoord) (bool, error) {
+	if !_geoIsFinite(&g) {
+		return false, ErrDomain
+	}
+	if t.Len() == 0 {
+		return false, nil
+	}
+	return t.Covers(geoToH3(&g, t.maxRes)), nil
+}
+
+/**
+ * Descendants returns the stored cells which are h or a descendant of h.
+ *
+ * @param h The cell to look under.
+ * @return The stored cells in depth first order, or ErrInvalidIndex if h is
+ *         not valid.
+ */
+func (t *CellTrie) Descendants(h H3Index) ([]H3Index, error) {
+	if !h3IsValid(h) {
+		return nil, ErrInvalidIndex
+	}
+
+	out := make([]H3Index, 0)
+	if node := t._node(h); node != nil {
+		out = _collectCellTrie(node, h, out)
+	}
+	return out, nil
+}
+
+func _collectCellTrie(node *cellTrieNode, h H3Index, out []H3Index) []H3Index {
+	if node.stored {
+		out = append(out, h)
+	}
+	for digit, child := range node.children {
+		if child != nil {
+			out = _collectCellTrie(child, makeDirectChild(h, Direction(digit)), out)
+		}
+	}
+	return out
+}
+
+/**
+ * Cells returns all the stored cells, ordered by base cell and then depth
+ * first.
+ */
+func (t *CellTrie) Cells() []H3Index {
+	out := make([]H3Index, 0, t.Len())
+	for baseCell, root := range t.roots {
+		if root != nil {
+			var h H3Index
+			setH3Index(&h, 0, baseCell, CENTER_DIGIT)
+			out = _collectCellTrie(root, h, out)
+		}
+	}
+	return out
+}
+
+/**
+ * LowestCommonAncestor returns the finest cell which is an ancestor of, or
+ * equal to, every stored cell.
+ *
+ * @return The common ancestor, and whether there is one. There is none when
+ *         the trie is empty or the cells are under different base cells.
+ */
+func (t *CellTrie) LowestCommonAncestor() (H3Index, bool) {
+	var h H3Index
+	var node *cellTrieNode
+	for baseCell, root := range t.roots {
+		if root == nil {
+			continue
+		}
+		if node != nil {
+			return H3_INVALID_INDEX, false
+		}
+		node = root
+		setH3Index(&h, 0, baseCell, CENTER_DIGIT)
+	}
+	if node == nil {
+		return H3_INVALID_INDEX, false
+	}
+
+	// Descend while the whole subtree is under a single child
+	for !node.stored {
+		var next *cellTrieNode
+		digit := 0
+		for d, child := range node.children {
+			if child != nil && child.count == node.count {
+				next, digit = child, d
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+		h = makeDirectChild(h, Direction(digit))
+	}
+	return h, true
+}
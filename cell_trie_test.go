@@ -0,0 +1,132 @@
+package h3
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCellTrie(t *testing.T) {
+	parent := H3Index(0x85283473fffffff)
+	cell := h3ToCenterChild(parent, 9)
+
+	t.Run("insert", func(t *testing.T) {
+		var trie CellTrie
+		require.Equal(t, 0, trie.Len())
+		require.NoError(t, trie.Insert(cell))
+		require.NoError(t, trie.Insert(cell))
+		require.NoError(t, trie.Insert(parent))
+		require.Equal(t, 2, trie.Len())
+		require.Equal(t, []H3Index{parent, cell}, trie.Cells())
+
+		require.True(t, trie.Contains(cell))
+		require.False(t, trie.Contains(h3ToParent(cell, 7)))
+		require.Equal(t, ErrInvalidIndex, trie.Insert(H3_INVALID_INDEX))
+		require.False(t, trie.Contains(H3_INVALID_INDEX))
+	})
+
+	t.Run("covers", func(t *testing.T) {
+		var trie CellTrie
+		require.NoError(t, trie.Insert(parent))
+		require.NoError(t, trie.Insert(cell))
+
+		covering, ok := trie.Covering(h3ToCenterChild(cell, 12))
+		require.True(t, ok)
+		require.Equal(t, cell, covering, "finest covering cell")
+
+		covering, ok = trie.Covering(h3ToParent(cell, 7))
+		require.True(t, ok)
+		require.Equal(t, parent, covering)
+
+		require.False(t, trie.Covers(h3ToParent(parent, 4)))
+		require.False(t, trie.Covers(0x8928308280fffff))
+		require.False(t, trie.Covers(H3_INVALID_INDEX))
+
+		var g GeoCoord
+		h3ToGeo(cell, &g)
+		covered, err := trie.CoversPoint(g)
+		require.NoError(t, err)
+		require.True(t, covered)
+
+		h3ToGeo(0x8928308280fffff, &g)
+		covered, err = trie.CoversPoint(g)
+		require.NoError(t, err)
+		require.False(t, covered)
+
+		_, err = trie.CoversPoint(GeoCoord{Lat: math.NaN()})
+		require.Equal(t, ErrDomain, err)
+	})
+
+	t.Run("descendants", func(t *testing.T) {
+		disk, err := GridDisk(cell, 3)
+		require.NoError(t, err)
+
+		var trie CellTrie
+		for _, h := range disk {
+			require.NoError(t, trie.Insert(h))
+		}
+		require.NoError(t, trie.Insert(0x8928308280fffff))
+
+		expected := make([]H3Index, 0)
+		for _, h := range disk {
+			if h3ToParent(h, 7) == h3ToParent(cell, 7) {
+				expected = append(expected, h)
+			}
+		}
+		descendants, err := trie.Descendants(h3ToParent(cell, 7))
+		require.NoError(t, err)
+		require.ElementsMatch(t, expected, descendants)
+
+		descendants, err = trie.Descendants(h3ToCenterChild(cell, 10))
+		require.NoError(t, err)
+		require.Empty(t, descendants)
+
+		_, err = trie.Descendants(H3_INVALID_INDEX)
+		require.Equal(t, ErrInvalidIndex, err)
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 3, 4, 0)
+		children := _directChildren(pentagon)
+
+		var trie CellTrie
+		for _, h := range children {
+			require.NoError(t, trie.Insert(h))
+		}
+		descendants, err := trie.Descendants(pentagon)
+		require.NoError(t, err)
+		require.ElementsMatch(t, children, descendants)
+
+		lca, ok := trie.LowestCommonAncestor()
+		require.True(t, ok)
+		require.Equal(t, pentagon, lca)
+	})
+
+	t.Run("lowestCommonAncestor", func(t *testing.T) {
+		var trie CellTrie
+		_, ok := trie.LowestCommonAncestor()
+		require.False(t, ok)
+
+		require.NoError(t, trie.Insert(cell))
+		lca, ok := trie.LowestCommonAncestor()
+		require.True(t, ok)
+		require.Equal(t, cell, lca)
+
+		other := h3ToCenterChild(_directChildren(h3ToParent(cell, 7))[3], 9)
+		require.NoError(t, trie.Insert(other))
+		lca, ok = trie.LowestCommonAncestor()
+		require.True(t, ok)
+		require.Equal(t, h3ToParent(cell, 7), lca)
+
+		require.NoError(t, trie.Insert(parent))
+		lca, ok = trie.LowestCommonAncestor()
+		require.True(t, ok)
+		require.Equal(t, parent, lca)
+
+		require.NoError(t, trie.Insert(0x8001fffffffffff))
+		_, ok = trie.LowestCommonAncestor()
+		require.False(t, ok, "different base cells")
+	})
+}
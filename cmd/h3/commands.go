@@ -0,0 +1,443 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/h3"
+)
+
+func init() {
+	commands["geoToH3"] = command{"-res N [lat,lon...]", geoToH3}
+	commands["h3ToGeo"] = command{"[index...]", h3ToGeo}
+	commands["h3ToGeoBoundary"] = command{"[index...]", h3ToGeoBoundary}
+	commands["kRing"] = command{"-k N [index...]", kRing}
+	commands["hexRing"] = command{"-k N [index...]", hexRing}
+	commands["h3Line"] = command{"start end", h3Line}
+	commands["h3Distance"] = command{"[origin destination...]", h3Distance}
+	commands["compact"] = command{"[index...]", compactCmd}
+	commands["uncompact"] = command{"-res N [index...]", uncompactCmd}
+	commands["polyfill"] = command{"-res N < polygon (lat,lon lines or GeoJSON)", polyfill}
+	commands["h3SetToMultiPolygon"] = command{"[index...]", h3SetToMultiPolygon}
+	commands["h3IndexesAreNeighbors"] = command{"origin destination", h3IndexesAreNeighbors}
+	commands["getH3UnidirectionalEdge"] = command{"origin destination", getH3UnidirectionalEdge}
+	commands["getH3UnidirectionalEdgesFromHexagon"] = command{"[index...]", getH3UnidirectionalEdgesFromHexagon}
+	commands["getOriginH3IndexFromUnidirectionalEdge"] = command{"[edge...]", getOriginH3IndexFromUnidirectionalEdge}
+	commands["getDestinationH3IndexFromUnidirectionalEdge"] = command{"[edge...]", getDestinationH3IndexFromUnidirectionalEdge}
+	commands["getH3IndexesFromUnidirectionalEdge"] = command{"[edge...]", getH3IndexesFromUnidirectionalEdge}
+	commands["getH3UnidirectionalEdgeBoundary"] = command{"[edge...]", getH3UnidirectionalEdgeBoundary}
+	commands["inspect"] = command{"[index...]", inspect}
+}
+
+/**
+ * pair reads exactly two indexes from the arguments or stdin.
+ */
+func (c *env) pair(args []string) (h3.H3Index, h3.H3Index, error) {
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(indexes) != 2 {
+		return 0, 0, fmt.Errorf("%s: expected two indexes: %w", c.name, errUsage)
+	}
+	return indexes[0], indexes[1], nil
+}
+
+/**
+ * cells writes one record per cell.
+ */
+func (c *env) cells(name string, cells []h3.H3Index) error {
+	for _, h := range cells {
+		if err := c.out.write(cellRecord(name, h)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/**
+ * fail annotates an error of the library with the failing input.
+ */
+func (c *env) fail(h h3.H3Index, err error) error {
+	return fmt.Errorf("%s: %s: %w", c.name, formatIndex(h), err)
+}
+
+func geoToH3(c *env, args []string) error {
+	flags := c.flags()
+	res := flags.Int("res", -1, "resolution")
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	coords, err := c.coords(args)
+	if err != nil {
+		return err
+	}
+	for _, g := range coords {
+		h, err := h3.GeoToCell(g, *res)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", c.name, formatCoord(g, ","), err)
+		}
+		if err := c.out.write(cellRecord("index", h)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func h3ToGeo(c *env, args []string) error {
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	for _, h := range indexes {
+		g, err := h3.CellToGeo(h)
+		if err != nil {
+			return c.fail(h, err)
+		}
+		r := cellRecord("index", h)
+		r.geometry = point(g)
+		if err := c.out.write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func h3ToGeoBoundary(c *env, args []string) error {
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	for _, h := range indexes {
+		boundary, err := h3.CellToBoundary(h)
+		if err != nil {
+			return c.fail(h, err)
+		}
+		r := cellRecord("index", h)
+		r.geometry = polygon(boundary.Verts)
+		if err := c.out.write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func kRing(c *env, args []string) error {
+	flags := c.flags()
+	k := flags.Int("k", 1, "grid distance")
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	for _, origin := range indexes {
+		disk, distances, err := h3.GridDiskDistances(origin, *k)
+		if err != nil {
+			return c.fail(origin, err)
+		}
+		for i := range disk {
+			r := cellRecord("index", disk[i]).add("distance", strconv.Itoa(distances[i]))
+			if err := c.out.write(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hexRing(c *env, args []string) error {
+	flags := c.flags()
+	k := flags.Int("k", 1, "grid distance")
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	for _, origin := range indexes {
+		ring, err := h3.GridRing(origin, *k)
+		if err != nil {
+			return c.fail(origin, err)
+		}
+		if err := c.cells("index", ring); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func h3Line(c *env, args []string) error {
+	start, end, err := c.pair(args)
+	if err != nil {
+		return err
+	}
+
+	path, exact, err := h3.GridPath(start, end)
+	if err != nil {
+		return c.fail(start, err)
+	}
+	if !exact {
+		c.warn("the path is not guaranteed to be the shortest")
+	}
+	return c.cells("index", path)
+}
+
+func h3Distance(c *env, args []string) error {
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	if len(indexes) == 0 || len(indexes)%2 != 0 {
+		return fmt.Errorf("%s: expected pairs of indexes: %w", c.name, errUsage)
+	}
+
+	for i := 0; i < len(indexes); i += 2 {
+		distance, exact, err := h3.GridDistance(indexes[i], indexes[i+1])
+		if err != nil {
+			return c.fail(indexes[i], err)
+		}
+		if !exact {
+			c.warn("the distance from %s to %s is an upper bound", formatIndex(indexes[i]), formatIndex(indexes[i+1]))
+		}
+		r := (&record{}).add("distance", strconv.Itoa(distance))
+		if err := c.out.write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func compactCmd(c *env, args []string) error {
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	compacted, err := h3.CompactCells(indexes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	return c.cells("index", compacted)
+}
+
+func uncompactCmd(c *env, args []string) error {
+	flags := c.flags()
+	res := flags.Int("res", -1, "resolution")
+	args, err := c.parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	cells, err := h3.UncompactCells(indexes, *res)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	return c.cells("index", cells)
+}
+
+func polyfill(c *env, args []string) error {
+	flags := c.flags()
+	res := flags.Int("res", -1, "resolution")
+	if _, err := c.parse(flags, args); err != nil {
+		return err
+	}
+
+	polygons, err := c.polygons()
+	if err != nil {
+		return err
+	}
+
+	seen := map[h3.H3Index]bool{}
+	for _, p := range polygons {
+		cells, err := h3.PolygonToCells(p, *res)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		for _, h := range cells {
+			if seen[h] {
+				continue
+			}
+			seen[h] = true
+			if err := c.out.write(cellRecord("index", h)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func h3SetToMultiPolygon(c *env, args []string) error {
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	m, err := h3.CellsToMultiPolygon(indexes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	return c.out.write(&record{geometry: multiPolygon(m)})
+}
+
+func h3IndexesAreNeighbors(c *env, args []string) error {
+	origin, destination, err := c.pair(args)
+	if err != nil {
+		return err
+	}
+	neighbors, err := h3.AreNeighborCells(origin, destination)
+	if err != nil {
+		return c.fail(origin, err)
+	}
+	return c.out.write((&record{}).add("neighbors", strconv.FormatBool(neighbors)))
+}
+
+/**
+ * edgeRecord describes an edge, drawn as a line in GeoJSON.
+ */
+func edgeRecord(edge h3.H3Index) *record {
+	r := &record{edge: edge}
+	return r.add("edge", formatIndex(edge))
+}
+
+func getH3UnidirectionalEdge(c *env, args []string) error {
+	origin, destination, err := c.pair(args)
+	if err != nil {
+		return err
+	}
+	edge, err := h3.CellsToDirectedEdge(origin, destination)
+	if err != nil {
+		return c.fail(origin, err)
+	}
+	return c.out.write(edgeRecord(edge))
+}
+
+func getH3UnidirectionalEdgesFromHexagon(c *env, args []string) error {
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	for _, h := range indexes {
+		edges, err := h3.OriginToDirectedEdges(h)
+		if err != nil {
+			return c.fail(h, err)
+		}
+		for _, edge := range edges {
+			if err := c.out.write(edgeRecord(edge)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+/**
+ * edgeCells writes the origin and/or destination of every edge.
+ */
+func edgeCells(c *env, args []string, origin bool, destination bool) error {
+	edges, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	for _, edge := range edges {
+		o, d, err := h3.DirectedEdgeToCells(edge)
+		if err != nil {
+			return c.fail(edge, err)
+		}
+
+		var r *record
+		switch {
+		case origin && destination:
+			r = (&record{}).add("origin", formatIndex(o)).add("destination", formatIndex(d))
+		case origin:
+			r = cellRecord("origin", o)
+		default:
+			r = cellRecord("destination", d)
+		}
+		if err := c.out.write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getOriginH3IndexFromUnidirectionalEdge(c *env, args []string) error {
+	return edgeCells(c, args, true, false)
+}
+
+func getDestinationH3IndexFromUnidirectionalEdge(c *env, args []string) error {
+	return edgeCells(c, args, false, true)
+}
+
+func getH3IndexesFromUnidirectionalEdge(c *env, args []string) error {
+	return edgeCells(c, args, true, true)
+}
+
+func getH3UnidirectionalEdgeBoundary(c *env, args []string) error {
+	edges, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	for _, edge := range edges {
+		boundary, err := h3.DirectedEdgeToBoundary(edge)
+		if err != nil {
+			return c.fail(edge, err)
+		}
+		r := edgeRecord(edge)
+		r.geometry = lineString(boundary.Verts)
+		if err := c.out.write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func inspect(c *env, args []string) error {
+	indexes, err := c.indexes(args)
+	if err != nil {
+		return err
+	}
+	for i, h := range indexes {
+		in := h3.Inspect(h)
+
+		digits := make([]string, len(in.Digits))
+		for j, d := range in.Digits {
+			digits[j] = strconv.Itoa(int(d.Digit))
+		}
+
+		r := (&record{}).
+			add("index", formatIndex(h)).
+			add("valid", strconv.FormatBool(in.Valid)).
+			add("reason", in.Reason).
+			add("highBit", strconv.Itoa(in.HighBit)).
+			add("mode", strconv.Itoa(int(in.Mode))).
+			add("reserved", strconv.Itoa(in.Reserved)).
+			add("resolution", strconv.Itoa(in.Resolution)).
+			add("baseCell", strconv.Itoa(in.BaseCell)).
+			add("pentagon", strconv.FormatBool(in.IsPentagon)).
+			add("polarPentagon", strconv.FormatBool(in.IsPolarPentagon)).
+			add("digits", strings.Join(digits, ""))
+		r.text = in.String()
+		if i > 0 {
+			r.text = "\n" + r.text
+		}
+		if in.Valid {
+			r.cell = h
+		}
+		if err := c.out.write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/h3"
+)
+
+var errUsage = errors.New("invalid usage")
+
+/**
+ * env carries the input and output of a single command invocation.
+ */
+type env struct {
+	name   string
+	stdin  io.Reader
+	stderr io.Writer
+	out    output
+}
+
+/**
+ * warn reports a problem that does not prevent the command from producing
+ * output.
+ */
+func (c *env) warn(format string, args ...interface{}) {
+	fmt.Fprintf(c.stderr, "h3: %s: %s\n", c.name, fmt.Sprintf(format, args...))
+}
+
+/**
+ * flags creates the flag set of the command.
+ */
+func (c *env) flags() *flag.FlagSet {
+	flags := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.Usage = func() {}
+	return flags
+}
+
+/**
+ * parse parses the command flags, reporting misuse as errUsage.
+ */
+func (c *env) parse(flags *flag.FlagSet, args []string) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, fmt.Errorf("%s: %v: %w", c.name, err, errUsage)
+	}
+	return flags.Args(), nil
+}
+
+/**
+ * lines returns the arguments or, when there are none, the non empty lines
+ * of stdin. Lines starting with # are skipped.
+ */
+func (c *env) lines(args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(c.stdin)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+/**
+ * indexes reads H3 indexes in hexadecimal from the arguments or stdin.
+ */
+func (c *env) indexes(args []string) ([]h3.H3Index, error) {
+	lines, err := c.lines(args)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]h3.H3Index, len(lines))
+	for i, line := range lines {
+		if out[i], err = parseIndex(line); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+/**
+ * coords reads "lat,lon" coordinates in degrees from the arguments or stdin.
+ * Two plain numeric arguments are accepted as a single coordinate.
+ */
+func (c *env) coords(args []string) ([]h3.GeoCoord, error) {
+	if len(args) == 2 && !strings.ContainsAny(args[0]+args[1], ", ") {
+		args = []string{args[0] + "," + args[1]}
+	}
+
+	lines, err := c.lines(args)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]h3.GeoCoord, len(lines))
+	for i, line := range lines {
+		if out[i], err = parseCoord(line); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+/**
+ * polygons reads polygons from stdin, either as GeoJSON or as "lat,lon"
+ * lines where blank lines separate the outer loop from the holes.
+ */
+func (c *env) polygons() ([]h3.GeoPolygon, error) {
+	data, err := ioutil.ReadAll(c.stdin)
+	if err != nil {
+		return nil, err
+	}
+
+	text := strings.TrimSpace(string(data))
+	if strings.HasPrefix(text, "{") {
+		return parseGeoJSON([]byte(text))
+	}
+
+	var loops []h3.Geofence
+	var verts []h3.GeoCoord
+	for _, line := range append(strings.Split(text, "\n"), "") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "" {
+			if len(verts) > 0 {
+				loops = append(loops, h3.NewGeofence(verts))
+				verts = nil
+			}
+			continue
+		}
+		coord, err := parseCoord(line)
+		if err != nil {
+			return nil, err
+		}
+		verts = append(verts, coord)
+	}
+
+	if len(loops) == 0 {
+		return nil, fmt.Errorf("%s: no polygon on stdin", c.name)
+	}
+	return []h3.GeoPolygon{h3.NewGeoPolygon(loops[0], loops[1:]...)}, nil
+}
+
+func parseIndex(s string) (h3.H3Index, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(s), "0x"), 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index %q", s)
+	}
+	return h3.H3Index(v), nil
+}
+
+func parseCoord(s string) (h3.GeoCoord, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+	if len(fields) != 2 {
+		return h3.GeoCoord{}, fmt.Errorf("invalid coordinate %q, expected lat,lon", s)
+	}
+
+	lat, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return h3.GeoCoord{}, fmt.Errorf("invalid latitude %q", fields[0])
+	}
+	lon, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return h3.GeoCoord{}, fmt.Errorf("invalid longitude %q", fields[1])
+	}
+	return fromDegrees(lat, lon), nil
+}
+
+func fromDegrees(lat float64, lon float64) h3.GeoCoord {
+	return h3.GeoCoord{Lat: lat * math.Pi / 180, Lon: lon * math.Pi / 180}
+}
+
+type geoJSON struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Geometry    *geoJSON        `json:"geometry"`
+	Features    []geoJSON       `json:"features"`
+}
+
+/**
+ * parseGeoJSON reads the polygons of a GeoJSON Polygon, MultiPolygon,
+ * Feature or FeatureCollection.
+ */
+func parseGeoJSON(data []byte) ([]h3.GeoPolygon, error) {
+	var g geoJSON
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, err
+	}
+	return g.polygons()
+}
+
+func (g *geoJSON) polygons() ([]h3.GeoPolygon, error) {
+	switch g.Type {
+	case "FeatureCollection":
+		var out []h3.GeoPolygon
+		for i := range g.Features {
+			polygons, err := g.Features[i].polygons()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, polygons...)
+		}
+		return out, nil
+	case "Feature":
+		if g.Geometry == nil {
+			return nil, nil
+		}
+		return g.Geometry.polygons()
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return nil, err
+		}
+		polygon, err := polygonFromRings(rings)
+		if err != nil {
+			return nil, err
+		}
+		return []h3.GeoPolygon{polygon}, nil
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &polygons); err != nil {
+			return nil, err
+		}
+		out := make([]h3.GeoPolygon, len(polygons))
+		for i := range polygons {
+			polygon, err := polygonFromRings(polygons[i])
+			if err != nil {
+				return nil, err
+			}
+			out[i] = polygon
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unsupported GeoJSON type %q", g.Type)
+}
+
+func polygonFromRings(rings [][][]float64) (h3.GeoPolygon, error) {
+	if len(rings) == 0 {
+		return h3.GeoPolygon{}, errors.New("polygon without rings")
+	}
+
+	loops := make([]h3.Geofence, len(rings))
+	for i, ring := range rings {
+		// GeoJSON rings repeat the first position at the end
+		for _, position := range ring {
+			if len(position) < 2 {
+				return h3.GeoPolygon{}, errors.New("invalid position")
+			}
+		}
+		if len(ring) > 1 && ring[0][0] == ring[len(ring)-1][0] && ring[0][1] == ring[len(ring)-1][1] {
+			ring = ring[:len(ring)-1]
+		}
+		verts := make([]h3.GeoCoord, len(ring))
+		for j, position := range ring {
+			verts[j] = fromDegrees(position[1], position[0])
+		}
+		loops[i] = h3.NewGeofence(verts)
+	}
+	return h3.NewGeoPolygon(loops[0], loops[1:]...), nil
+}
@@ -0,0 +1,87 @@
+/*
+Command h3 exposes the core H3 functions on the command line.
+
+Usage:
+
+	h3 [-format text|csv|geojson] <command> [flags] [args...]
+
+Indexes and coordinates are read from the arguments or, when there are none,
+from stdin one per line. Coordinates are "lat,lon" pairs in degrees. Run h3
+without arguments for the list of commands.
+*/
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(c *env, args []string) error
+}
+
+var commands = map[string]command{}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "h3:", err)
+		if errors.Is(err, errUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("h3", flag.ContinueOnError)
+	flags.SetOutput(stdout)
+	format := flags.String("format", "text", "output format: text, csv or geojson")
+	flags.Usage = func() { usage(stdout, flags) }
+
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() == 0 {
+		usage(stdout, flags)
+		return errUsage
+	}
+
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unknown command %q", flags.Arg(0))
+	}
+
+	out, err := newOutput(*format, stdout)
+	if err != nil {
+		return err
+	}
+
+	c := &env{name: flags.Arg(0), stdin: stdin, stderr: stderr, out: out}
+	if err := cmd.run(c, flags.Args()[1:]); err != nil {
+		return err
+	}
+	return out.close()
+}
+
+func usage(w io.Writer, flags *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: h3 [flags] <command> [command flags] [args...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "flags:")
+	flags.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-45s %s\n", name, commands[name].usage)
+	}
+}
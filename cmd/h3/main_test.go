@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func runH3(t *testing.T, stdin string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	err := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestRun(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		out, err := runH3(t, "", "geoToH3", "-res", "9", "37.775938728915946", "-122.41795063018799")
+		require.NoError(t, err)
+		require.Equal(t, "8928308280fffff\n", out)
+
+		out, err = runH3(t, "", "h3ToGeo", "8928308280fffff")
+		require.NoError(t, err)
+		require.Equal(t, "8928308280fffff 37.776702349 -122.418459323\n", out)
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		out, err := runH3(t, "# cells\n8928308280fffff\n\n8928308283bffff\n", "h3Distance")
+		require.NoError(t, err)
+		require.Equal(t, "1\n", out)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		out, err := runH3(t, "", "-format", "csv", "kRing", "-k", "1", "8928308280fffff")
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Len(t, lines, 8)
+		require.Equal(t, "index,distance", lines[0])
+		require.Equal(t, "8928308280fffff,0", lines[1])
+	})
+
+	t.Run("geojson", func(t *testing.T) {
+		out, err := runH3(t, "", "-format", "geojson", "kRing", "-k", "1", "8928308280fffff")
+		require.NoError(t, err)
+
+		var collection struct {
+			Features []struct {
+				Geometry struct {
+					Type string
+				}
+			}
+		}
+		require.NoError(t, json.Unmarshal([]byte(out), &collection))
+		require.Len(t, collection.Features, 7)
+		require.Equal(t, "Polygon", collection.Features[0].Geometry.Type)
+	})
+
+	t.Run("compact", func(t *testing.T) {
+		children, err := runH3(t, "", "uncompact", "-res", "6", "85283473fffffff")
+		require.NoError(t, err)
+		require.Len(t, strings.Fields(children), 7)
+
+		out, err := runH3(t, children, "compact")
+		require.NoError(t, err)
+		require.Equal(t, "85283473fffffff\n", out)
+	})
+
+	t.Run("polyfill", func(t *testing.T) {
+		outline, err := runH3(t, "", "-format", "geojson", "h3SetToMultiPolygon", "8928308280fffff")
+		require.NoError(t, err)
+
+		out, err := runH3(t, outline, "polyfill", "-res", "9")
+		require.NoError(t, err)
+		require.Equal(t, "8928308280fffff\n", out)
+	})
+
+	t.Run("edges", func(t *testing.T) {
+		edge, err := runH3(t, "", "getH3UnidirectionalEdge", "8928308280fffff", "8928308283bffff")
+		require.NoError(t, err)
+
+		out, err := runH3(t, edge, "getH3IndexesFromUnidirectionalEdge")
+		require.NoError(t, err)
+		require.Equal(t, "8928308280fffff 8928308283bffff\n", out)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := runH3(t, "")
+		require.True(t, errors.Is(err, errUsage))
+
+		_, err = runH3(t, "", "-format", "xml", "h3ToGeo", "8928308280fffff")
+		require.True(t, errors.Is(err, errUsage))
+
+		_, err = runH3(t, "", "h3ToGeo", "zz")
+		require.Error(t, err)
+
+		_, err = runH3(t, "", "h3ToGeo", "0")
+		require.Error(t, err)
+	})
+}
+
+func TestInspect(t *testing.T) {
+	out, err := runH3(t, "", "inspect", "8928308280fffff", "8928308280ffff0")
+	require.NoError(t, err)
+	require.Contains(t, out, "valid       true\n")
+	require.Contains(t, out, "valid       false: unused digit 6 at res 14 is not 7\n")
+
+	out, err = runH3(t, "", "-format", "csv", "inspect", "8928308280fffff")
+	require.NoError(t, err)
+	require.Contains(t, out, "8928308280fffff,true,,0,1,0,9,20,false,false,060405003777777\n")
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-courier/h3"
+)
+
+/**
+ * Kinds of geometry, named as in GeoJSON.
+ */
+const (
+	geometryPoint        = "Point"
+	geometryLineString   = "LineString"
+	geometryPolygon      = "Polygon"
+	geometryMultiPolygon = "MultiPolygon"
+)
+
+/**
+ * geometry holds coordinates as polygons of rings of vertices; a Point uses
+ * the first vertex, a LineString the first ring and a Polygon the first
+ * polygon.
+ */
+type geometry struct {
+	kind   string
+	coords [][][]h3.GeoCoord
+}
+
+func point(g h3.GeoCoord) *geometry {
+	return &geometry{kind: geometryPoint, coords: [][][]h3.GeoCoord{{{g}}}}
+}
+
+func lineString(verts []h3.GeoCoord) *geometry {
+	return &geometry{kind: geometryLineString, coords: [][][]h3.GeoCoord{{verts}}}
+}
+
+func polygon(rings ...[]h3.GeoCoord) *geometry {
+	return &geometry{kind: geometryPolygon, coords: [][][]h3.GeoCoord{rings}}
+}
+
+func multiPolygon(m h3.GeoMultiPolygon) *geometry {
+	g := &geometry{kind: geometryMultiPolygon}
+	for _, p := range m.Polygons() {
+		rings := [][]h3.GeoCoord{p.Geofence().Verts()}
+		for _, hole := range p.Holes() {
+			rings = append(rings, hole.Verts())
+		}
+		g.coords = append(g.coords, rings)
+	}
+	return g
+}
+
+/**
+ * record is a single result row of named values with an optional geometry.
+ * When cell or edge is set and there is no geometry, GeoJSON output draws
+ * the cell boundary or the edge. A non empty text replaces the values in the
+ * text format.
+ */
+type record struct {
+	names    []string
+	values   []string
+	cell     h3.H3Index
+	edge     h3.H3Index
+	geometry *geometry
+	text     string
+}
+
+func (r *record) add(name string, value string) *record {
+	r.names = append(r.names, name)
+	r.values = append(r.values, value)
+	return r
+}
+
+func cellRecord(name string, h h3.H3Index) *record {
+	r := &record{cell: h}
+	return r.add(name, formatIndex(h))
+}
+
+type output interface {
+	write(r *record) error
+	close() error
+}
+
+func newOutput(format string, w io.Writer) (output, error) {
+	switch format {
+	case "text":
+		return &textOutput{w: w}, nil
+	case "csv":
+		return &csvOutput{w: csv.NewWriter(w)}, nil
+	case "geojson":
+		return &geoJSONOutput{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q: %w", format, errUsage)
+}
+
+func formatIndex(h h3.H3Index) string {
+	return strconv.FormatUint(uint64(h), 16)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 9, 64)
+}
+
+func toDegrees(g h3.GeoCoord) (float64, float64) {
+	return g.Lat * 180 / math.Pi, g.Lon * 180 / math.Pi
+}
+
+func formatCoord(g h3.GeoCoord, sep string) string {
+	lat, lon := toDegrees(g)
+	return formatFloat(lat) + sep + formatFloat(lon)
+}
+
+/**
+ * textOutput prints the values of every record separated by spaces, followed
+ * by the coordinates of its geometry in degrees.
+ */
+type textOutput struct {
+	w io.Writer
+}
+
+func (o *textOutput) write(r *record) error {
+	if r.text != "" {
+		_, err := fmt.Fprintln(o.w, strings.TrimSuffix(r.text, "\n"))
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Join(r.values, " "))
+
+	if g := r.geometry; g != nil {
+		if g.kind == geometryPoint {
+			if b.Len() > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(formatCoord(g.coords[0][0][0], " "))
+		} else {
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			switch g.kind {
+			case geometryLineString:
+				writeVerts(&b, g.coords[0][0], 0)
+			case geometryPolygon:
+				writeRings(&b, g.coords[0], 0)
+			default:
+				b.WriteString("{\n")
+				for _, rings := range g.coords {
+					writeRings(&b, rings, 1)
+				}
+				b.WriteString("}\n")
+			}
+		}
+	}
+
+	_, err := fmt.Fprintln(o.w, strings.TrimSuffix(b.String(), "\n"))
+	return err
+}
+
+func (o *textOutput) close() error {
+	return nil
+}
+
+/**
+ * writeVerts prints vertices as a brace delimited block, as the H3 C command
+ * line tools do.
+ */
+func writeVerts(b *strings.Builder, verts []h3.GeoCoord, depth int) {
+	indent := strings.Repeat("   ", depth)
+	b.WriteString(indent + "{\n")
+	for _, v := range verts {
+		b.WriteString(indent + "   " + formatCoord(v, " ") + "\n")
+	}
+	b.WriteString(indent + "}\n")
+}
+
+/**
+ * writeRings prints a polygon; one with holes gets a block per ring.
+ */
+func writeRings(b *strings.Builder, rings [][]h3.GeoCoord, depth int) {
+	if len(rings) == 1 {
+		writeVerts(b, rings[0], depth)
+		return
+	}
+
+	indent := strings.Repeat("   ", depth)
+	b.WriteString(indent + "{\n")
+	for _, verts := range rings {
+		writeVerts(b, verts, depth+1)
+	}
+	b.WriteString(indent + "}\n")
+}
+
+/**
+ * csvOutput writes a header taken from the first record, followed by one row
+ * per record. Points add lat and lon columns, other geometries a WKT column.
+ */
+type csvOutput struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (o *csvOutput) write(r *record) error {
+	row := append([]string{}, r.values...)
+	names := append([]string{}, r.names...)
+
+	if g := r.geometry; g != nil {
+		if g.kind == geometryPoint {
+			lat, lon := toDegrees(g.coords[0][0][0])
+			names = append(names, "lat", "lon")
+			row = append(row, formatFloat(lat), formatFloat(lon))
+		} else {
+			names = append(names, "wkt")
+			row = append(row, wkt(g))
+		}
+	}
+
+	if !o.header {
+		o.header = true
+		if err := o.w.Write(names); err != nil {
+			return err
+		}
+	}
+	return o.w.Write(row)
+}
+
+func (o *csvOutput) close() error {
+	o.w.Flush()
+	return o.w.Error()
+}
+
+func wkt(g *geometry) string {
+	ring := func(verts []h3.GeoCoord, closed bool) string {
+		parts := make([]string, 0, len(verts)+1)
+		for _, v := range verts {
+			lat, lon := toDegrees(v)
+			parts = append(parts, formatFloat(lon)+" "+formatFloat(lat))
+		}
+		if closed && len(verts) > 0 {
+			parts = append(parts, parts[0])
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+	polygon := func(rings [][]h3.GeoCoord) string {
+		parts := make([]string, len(rings))
+		for i := range rings {
+			parts[i] = ring(rings[i], true)
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	}
+
+	switch g.kind {
+	case geometryLineString:
+		return "LINESTRING " + ring(g.coords[0][0], false)
+	case geometryPolygon:
+		return "POLYGON " + polygon(g.coords[0])
+	default:
+		parts := make([]string, len(g.coords))
+		for i := range g.coords {
+			parts[i] = polygon(g.coords[i])
+		}
+		return "MULTIPOLYGON (" + strings.Join(parts, ", ") + ")"
+	}
+}
+
+/**
+ * geoJSONOutput collects the records as features of a FeatureCollection,
+ * written when the output is closed.
+ */
+type geoJSONOutput struct {
+	w        io.Writer
+	features []geoJSONFeature
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Properties map[string]string `json:"properties"`
+	Geometry   *geoJSONGeometry  `json:"geometry"`
+}
+
+func (o *geoJSONOutput) write(r *record) error {
+	f := geoJSONFeature{Type: "Feature", Properties: map[string]string{}}
+	for i := range r.names {
+		f.Properties[r.names[i]] = r.values[i]
+	}
+
+	g := r.geometry
+	if g == nil && r.cell != 0 {
+		if boundary, err := h3.CellToBoundary(r.cell); err == nil {
+			g = polygon(boundary.Verts)
+		}
+	}
+	if g == nil && r.edge != 0 {
+		if boundary, err := h3.DirectedEdgeToBoundary(r.edge); err == nil {
+			g = lineString(boundary.Verts)
+		}
+	}
+	if g != nil {
+		f.Geometry = geoJSONGeometryOf(g)
+	}
+
+	o.features = append(o.features, f)
+	return nil
+}
+
+func (o *geoJSONOutput) close() error {
+	features := o.features
+	if features == nil {
+		features = []geoJSONFeature{}
+	}
+
+	e := json.NewEncoder(o.w)
+	return e.Encode(struct {
+		Type     string           `json:"type"`
+		Features []geoJSONFeature `json:"features"`
+	}{"FeatureCollection", features})
+}
+
+func geoJSONGeometryOf(g *geometry) *geoJSONGeometry {
+	position := func(v h3.GeoCoord) []float64 {
+		lat, lon := toDegrees(v)
+		return []float64{lon, lat}
+	}
+	ring := func(verts []h3.GeoCoord, closed bool) [][]float64 {
+		out := make([][]float64, 0, len(verts)+1)
+		for _, v := range verts {
+			out = append(out, position(v))
+		}
+		if closed && len(verts) > 0 {
+			out = append(out, position(verts[0]))
+		}
+		return out
+	}
+	polygon := func(rings [][]h3.GeoCoord) [][][]float64 {
+		out := make([][][]float64, len(rings))
+		for i := range rings {
+			out[i] = ring(rings[i], true)
+		}
+		return out
+	}
+
+	out := &geoJSONGeometry{Type: g.kind}
+	switch g.kind {
+	case geometryPoint:
+		out.Coordinates = position(g.coords[0][0][0])
+	case geometryLineString:
+		out.Coordinates = ring(g.coords[0][0], false)
+	case geometryPolygon:
+		out.Coordinates = polygon(g.coords[0])
+	default:
+		polygons := make([][][][]float64, len(g.coords))
+		for i := range g.coords {
+			polygons[i] = polygon(g.coords[i])
+		}
+		out.Coordinates = polygons
+	}
+	return out
+}
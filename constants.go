@@ -64,6 +64,15 @@ const NUM_PENT_VERTS = 5
 /** The number of pentagons per resolution **/
 const NUM_PENTAGONS = 12
 
+/**
+ * The most cells a slice is made to hold: 2^45 cells on 64-bit platforms,
+ * where make panics for slices of more than the 2^48 bytes the runtime
+ * allocates at once, and 2^29 cells on 32-bit ones. Sizes beyond it are
+ * rejected with an error rather than a panic. This does not limit the
+ * size of a request, which is left to the caller and to Options.
+ */
+const MAX_SLICE_CELLS = 1 << (29 + 16*(^uint(0)>>63))
+
 type H3Mode int
 
 /** H3 index modes */
@@ -0,0 +1,296 @@
+package h3
+
+import "context"
+
+/** Number of units of work between two checks of the context */
+const CONTEXT_CHECK_INTERVAL = 1024
+
+/** Size of an index, in bytes */
+const H3_INDEX_BYTES = 8
+
+/** Approximate bytes held by the outline of each input cell */
+const OUTLINE_BYTES_PER_CELL = 48
+
+/** Approximate bytes held by each edge of an outline, until it is output */
+const OUTLINE_BYTES_PER_EDGE = 160
+
+/** Size of a coordinate of an output geofence, in bytes */
+const GEO_COORD_BYTES = 16
+
+/**
+ * @brief Limits on the work of an operation taking a context
+ *
+ * The zero value sets no limits.
+ */
+type Options struct {
+	MaxCells  int   ///< most cells to output, or to take as input for outlines; 0 for no limit
+	MaxMemory int64 ///< most bytes to allocate for the output and working buffers; 0 for no limit
+}
+
+/**
+ * @brief The cancellation and limits checked along an operation
+ *
+ * A nil budget never aborts.
+ */
+type workBudget struct {
+	ctx      context.Context
+	opts     Options
+	steps    int     ///< units of work done
+	reserved float64 ///< bytes reserved
+	err      error   ///< why the operation was aborted
+}
+
+/**
+ * reserve accounts for memory the operation is about to allocate.
+ *
+ * @param bytes Number of bytes.
+ * @return Whether the operation may go on.
+ */
+func (b *workBudget) reserve(bytes float64) bool {
+	if b == nil {
+		return true
+	}
+	b.reserved += bytes
+	if b.err == nil && b.opts.MaxMemory > 0 && b.reserved > float64(b.opts.MaxMemory) {
+		b.err = ErrMemoryLimit
+	}
+	return b.err == nil
+}
+
+/**
+ * check counts a unit of work, and looks at the context every
+ * CONTEXT_CHECK_INTERVAL units.
+ *
+ * @param cells Number of cells output so far.
+ * @return Whether the operation may go on.
+ */
+func (b *workBudget) check(cells int) bool {
+	if b == nil {
+		return true
+	}
+	if b.err == nil && b.opts.MaxCells > 0 && cells > b.opts.MaxCells {
+		b.err = ErrCellLimit
+	}
+	if b.err == nil && b.steps%CONTEXT_CHECK_INTERVAL == 0 {
+		b.err = b.ctx.Err()
+	}
+	b.steps++
+	return b.err == nil
+}
+
+/**
+ * _newWorkBudget creates the budget of an operation, which is aborted at
+ * once if the context is done already.
+ */
+func _newWorkBudget(ctx context.Context, opts Options) (*workBudget, error) {
+	if opts.MaxCells < 0 || opts.MaxMemory < 0 {
+		return nil, ErrDomain
+	}
+	return &workBudget{ctx: ctx, opts: opts}, ctx.Err()
+}
+
+/**
+ * PolygonToCellsContext is PolygonToCells, which stops when the context is
+ * done or a limit is exceeded.
+ *
+ * @param ctx The context.
+ * @param geoPolygon The geofence and holes defining the area, in radians.
+ * @param res The resolution of the cells (0-15).
+ * @param opts The limits. MaxCells bounds the cells found. The buffers are
+ *             sized after the bounding box of the polygon, before any cell
+ *             is found, and MaxMemory bounds them.
+ * @return The cells covering the polygon, or the error of the context,
+ *         ErrCellLimit or ErrMemoryLimit.
+ */
+func PolygonToCellsContext(ctx context.Context, geoPolygon GeoPolygon, res int, opts Options) ([]H3Index, error) {
+	budget, err := _newWorkBudget(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := _validatePolygonToCells(&geoPolygon, res); err != nil || geoPolygon.geofence.IsZero() {
+		return nil, err
+	}
+
+	size := maxPolyfillSize(&geoPolygon, res)
+	// the output, and the search and found buffers, which are hash sets of
+	// the estimated size and cannot grow. MaxCells bounds the cells found,
+	// as they are found.
+	if !budget.reserve(3 * H3_INDEX_BYTES * float64(size)) {
+		return nil, budget.err
+	}
+	if size > MAX_SLICE_CELLS {
+		return nil, ErrDomain
+	}
+	out := make([]H3Index, size)
+	if _polyfillInternal(&geoPolygon, res, out, budget) != 0 {
+		if budget.err != nil {
+			return nil, budget.err
+		}
+		return nil, ErrFailed
+	}
+
+	n := 0
+	for i := range out {
+		if out[i] != H3_INVALID_INDEX {
+			out[n] = out[i]
+			n++
+		}
+	}
+	return out[:n], nil
+}
+
+/**
+ * UncompactCellsContext is UncompactCells, which stops when the context is
+ * done or a limit is exceeded.
+ *
+ * @param ctx The context.
+ * @param compactedSet Cells of resolution res or coarser.
+ * @param res The resolution to expand to.
+ * @param opts The limits, which are known to be exceeded up front.
+ * @return The cells at resolution res, or the error of the context,
+ *         ErrCellLimit or ErrMemoryLimit.
+ */
+func UncompactCellsContext(ctx context.Context, compactedSet []H3Index, res int, opts Options) ([]H3Index, error) {
+	budget, err := _newWorkBudget(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := _validateUncompact(compactedSet, res); err != nil {
+		return nil, err
+	}
+
+	// the limits come first, and the size is checked as it is summed so
+	// that it can not overflow
+	size := 0
+	for _, h := range compactedSet {
+		n := _numChildren(h, res)
+		size += n
+		if !budget.check(size) || !budget.reserve(H3_INDEX_BYTES*float64(n)) {
+			return nil, budget.err
+		}
+		if size > MAX_SLICE_CELLS {
+			return nil, ErrDomain
+		}
+	}
+
+	out := make([]H3Index, 0, size)
+	for _, h := range compactedSet {
+		if out = _appendChildrenContext(out, h, h3IsPentagon(h), res, budget); budget.err != nil {
+			return nil, budget.err
+		}
+	}
+	return out, nil
+}
+
+/**
+ * _appendChildrenContext appends the children of a cell like
+ * _appendChildren, in batches of at most CONTEXT_CHECK_INTERVAL children,
+ * each of which is a unit of work.
+ */
+func _appendChildrenContext(dst []H3Index, h H3Index, isPentagon bool, res int, budget *workBudget) []H3Index {
+	if maxH3ToChildrenSize(h, res) <= CONTEXT_CHECK_INTERVAL {
+		if !budget.check(len(dst)) {
+			return dst
+		}
+		return _appendChildren(dst, h, isPentagon, res)
+	}
+	for d := CENTER_DIGIT; d < NUM_DIGITS && budget.err == nil; d++ {
+		if isPentagon && d == K_AXES_DIGIT {
+			continue
+		}
+		dst = _appendChildrenContext(dst, makeDirectChild(h, d), isPentagon && d == CENTER_DIGIT, res, budget)
+	}
+	return dst
+}
+
+/**
+ * GridDiskContext is GridDisk, which stops when the context is done or a
+ * limit is exceeded. The disk is searched breadth first, so that the search
+ * can stop at any cell.
+ *
+ * @param ctx The context.
+ * @param origin Origin cell.
+ * @param k Distance, k >= 0.
+ * @param opts The limits. The buffers are sized for a disk around a
+ *             hexagon, or for MaxCells cells and the neighbors of one
+ *             more, before any cell is found.
+ * @return The cells in order of increasing distance from the origin, or the
+ *         error of the context, ErrCellLimit or ErrMemoryLimit.
+ */
+func GridDiskContext(ctx context.Context, origin H3Index, k int, opts Options) ([]H3Index, error) {
+	budget, err := _newWorkBudget(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !h3IsValid(origin) {
+		return nil, ErrInvalidIndex
+	}
+	if k < 0 {
+		return nil, ErrDomain
+	}
+	k, size := _gridDiskSize(k, H3_GET_RESOLUTION(origin))
+
+	// the search stops once it has found more than MaxCells cells, which is
+	// while expanding a cell at most MAX_ONE_RING_SIZE cells past the limit
+	if opts.MaxCells > 0 && size > float64(opts.MaxCells+MAX_ONE_RING_SIZE) {
+		size = float64(opts.MaxCells + MAX_ONE_RING_SIZE)
+	}
+	// the output, and the set of cells found
+	if !budget.reserve(3 * H3_INDEX_BYTES * size) {
+		return nil, budget.err
+	}
+	if size > MAX_SLICE_CELLS {
+		return nil, ErrDomain
+	}
+	out := make([]H3Index, int(size))
+	n := _gridDiskDistancesBfs(origin, k, out, nil, budget)
+	if !budget.check(n) {
+		return nil, budget.err
+	}
+	return out[:n], nil
+}
+
+/**
+ * CellsToMultiPolygonContext is CellsToMultiPolygon, which stops when the
+ * context is done or a limit is exceeded.
+ *
+ * @param ctx The context.
+ * @param h3Set Cells of a single resolution, without duplicates.
+ * @param opts The limits. MaxCells bounds the number of input cells, and
+ *             the memory is estimated from OUTLINE_BYTES_PER_CELL and
+ *             OUTLINE_BYTES_PER_EDGE as the outline grows, and from
+ *             GEO_COORD_BYTES as its loops are walked.
+ * @return The outlines, or the error of the context, ErrCellLimit or
+ *         ErrMemoryLimit. ErrFailed is returned when a hole could not be
+ *         assigned to an outer loop.
+ */
+func CellsToMultiPolygonContext(ctx context.Context, h3Set []H3Index, opts Options) (GeoMultiPolygon, error) {
+	budget, err := _newWorkBudget(ctx, opts)
+	if err != nil {
+		return GeoMultiPolygon{}, err
+	}
+	if !budget.check(len(h3Set)) {
+		return GeoMultiPolygon{}, budget.err
+	}
+	if err := _validateCellsToMultiPolygon(h3Set); err != nil {
+		return GeoMultiPolygon{}, err
+	}
+
+	var graph outlineGraph
+	_h3SetToOutlineGraph(h3Set, &graph, budget)
+	if budget.err != nil {
+		return GeoMultiPolygon{}, budget.err
+	}
+	loops := _outlineGraphToGeofences(&graph, budget)
+	if budget.err != nil {
+		return GeoMultiPolygon{}, budget.err
+	}
+	multiPolygon, result := _geofencesToMultiPolygon(loops, budget)
+	if budget.err != nil {
+		return GeoMultiPolygon{}, budget.err
+	}
+	if result != NORMALIZATION_SUCCESS {
+		return GeoMultiPolygon{}, ErrFailed
+	}
+	return multiPolygon, nil
+}
@@ -0,0 +1,217 @@
+package h3
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countdownContext is canceled once Err has been called a number of times
+type countdownContext struct {
+	context.Context
+	calls int
+}
+
+func (c *countdownContext) Err() error {
+	if c.calls <= 0 {
+		return context.Canceled
+	}
+	c.calls--
+	return nil
+}
+
+func TestContextOperations(t *testing.T) {
+	polygon := NewGeoPolygon(NewGeofence(sfVerts))
+	var pentagon H3Index
+	setH3Index(&pentagon, 4, 4, 0)
+	disk, err := GridDisk(0x8928308280fffff, 4)
+	require.NoError(t, err)
+
+	operations := map[string]struct {
+		run      func(ctx context.Context, opts Options) (int, error)
+		expected func() (int, error)
+	}{
+		"PolygonToCells": {
+			run: func(ctx context.Context, opts Options) (int, error) {
+				cells, err := PolygonToCellsContext(ctx, polygon, 9, opts)
+				return len(cells), err
+			},
+			expected: func() (int, error) {
+				cells, err := PolygonToCells(polygon, 9)
+				return len(cells), err
+			},
+		},
+		"UncompactCells": {
+			run: func(ctx context.Context, opts Options) (int, error) {
+				cells, err := UncompactCellsContext(ctx, []H3Index{0x85283473fffffff, pentagon}, 9, opts)
+				return len(cells), err
+			},
+			expected: func() (int, error) {
+				cells, err := UncompactCells([]H3Index{0x85283473fffffff, pentagon}, 9)
+				return len(cells), err
+			},
+		},
+		"GridDisk": {
+			run: func(ctx context.Context, opts Options) (int, error) {
+				cells, err := GridDiskContext(ctx, pentagon, 30, opts)
+				return len(cells), err
+			},
+			expected: func() (int, error) {
+				cells, err := GridDisk(pentagon, 30)
+				return len(cells), err
+			},
+		},
+		"CellsToMultiPolygon": {
+			run: func(ctx context.Context, opts Options) (int, error) {
+				multiPolygon, err := CellsToMultiPolygonContext(ctx, disk, opts)
+				if err != nil {
+					return 0, err
+				}
+				return multiPolygon.Polygons()[0].Geofence().numVerts, nil
+			},
+			expected: func() (int, error) {
+				multiPolygon, err := CellsToMultiPolygon(disk)
+				return multiPolygon.Polygons()[0].Geofence().numVerts, err
+			},
+		},
+	}
+
+	for name, op := range operations {
+		t.Run(name, func(t *testing.T) {
+			expected, err := op.expected()
+			require.NoError(t, err)
+			require.True(t, expected > 1)
+
+			n, err := op.run(context.Background(), Options{})
+			require.NoError(t, err)
+			require.Equal(t, expected, n)
+
+			_, err = op.run(context.Background(), Options{MaxCells: len(disk) - 1})
+			require.Equal(t, ErrCellLimit, err)
+			require.True(t, errors.Is(err, ErrLimitExceeded))
+			_, err = op.run(context.Background(), Options{MaxMemory: 1000})
+			require.Equal(t, ErrMemoryLimit, err)
+			require.True(t, errors.Is(err, ErrLimitExceeded))
+			_, err = op.run(context.Background(), Options{MaxCells: -1})
+			require.Equal(t, ErrDomain, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = op.run(ctx, Options{})
+			require.Equal(t, context.Canceled, err)
+
+			// canceled along the way
+			_, err = op.run(&countdownContext{Context: context.Background(), calls: 1}, Options{})
+			require.Equal(t, context.Canceled, err)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx := context.Background()
+		_, err := PolygonToCellsContext(ctx, polygon, 16, Options{})
+		require.Equal(t, ErrDomain, err)
+		_, err = UncompactCellsContext(ctx, []H3Index{H3_INVALID_INDEX}, 9, Options{})
+		require.Equal(t, ErrInvalidIndex, err)
+		_, err = GridDiskContext(ctx, pentagon, -1, Options{})
+		require.Equal(t, ErrDomain, err)
+		_, err = CellsToMultiPolygonContext(ctx, append(disk, disk[0]), Options{})
+		require.Equal(t, ErrDuplicateInput, err)
+
+		cells, err := PolygonToCellsContext(ctx, GeoPolygon{}, 9, Options{})
+		require.NoError(t, err)
+		require.Empty(t, cells)
+
+		// a ring around the pole, of which the hole is not assigned
+		pole := geoToH3(&GeoCoord{Lat: M_PI_2, Lon: 0}, 5)
+		poleDisk, err := GridDisk(pole, 3)
+		require.NoError(t, err)
+		ring, err := GridRing(pole, 1)
+		require.NoError(t, err)
+		_, err = CellsToMultiPolygonContext(ctx, subtractCells(poleDisk, ring), Options{})
+		require.Equal(t, ErrFailed, err)
+	})
+}
+
+func TestContextTooLarge(t *testing.T) {
+	// requests for more cells than a slice holds fail on the limits set,
+	// and only fail with ErrDomain without them
+	ctx := context.Background()
+	res0 := make([]H3Index, res0IndexCount())
+	getRes0Indexes(res0)
+	sphere := NewGeoPolygon(NewGeofence([]GeoCoord{{-1, -1.5}, {1, -1.5}, {1, 1.5}, {-1, 1.5}}))
+
+	_, err := GridDiskContext(ctx, 0x8f2830828052d25, math.MaxInt32, Options{MaxCells: 100})
+	require.Equal(t, ErrCellLimit, err)
+	_, err = GridDiskContext(ctx, 0x8f2830828052d25, math.MaxInt32, Options{MaxMemory: 1 << 30})
+	require.Equal(t, ErrMemoryLimit, err)
+	_, err = GridDiskContext(ctx, 0x8f2830828052d25, math.MaxInt32, Options{})
+	require.Equal(t, ErrDomain, err)
+
+	_, err = UncompactCellsContext(ctx, res0, 15, Options{MaxCells: 1 << 30})
+	require.Equal(t, ErrCellLimit, err)
+	_, err = UncompactCellsContext(ctx, res0, 15, Options{MaxMemory: 1 << 30})
+	require.Equal(t, ErrMemoryLimit, err)
+	_, err = UncompactCellsContext(ctx, res0, 15, Options{})
+	require.Equal(t, ErrDomain, err)
+
+	_, err = PolygonToCellsContext(ctx, sphere, 15, Options{MaxMemory: 1 << 30})
+	require.Equal(t, ErrMemoryLimit, err)
+	_, err = PolygonToCellsContext(ctx, sphere, 15, Options{})
+	require.Equal(t, ErrDomain, err)
+}
+
+func TestContextAllocations(t *testing.T) {
+	// bytes allocated by a call
+	allocated := func(f func()) uint64 {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		f()
+		runtime.ReadMemStats(&after)
+		return after.TotalAlloc - before.TotalAlloc
+	}
+	opts := Options{MaxCells: 100}
+
+	t.Run("GridDisk", func(t *testing.T) {
+		// a disk of 12 million cells
+		var err error
+		bytes := allocated(func() {
+			_, err = GridDiskContext(context.Background(), 0x8f2830828052d25, 2000, opts)
+		})
+		require.Equal(t, ErrCellLimit, err)
+		require.True(t, bytes < 1<<20, "allocated %d bytes", bytes)
+
+		cells, err := GridDiskContext(context.Background(), 0x8f2830828052d25, 5, opts)
+		require.NoError(t, err)
+		require.Len(t, cells, 91)
+	})
+
+	t.Run("PolygonToCells", func(t *testing.T) {
+		// buffers of 8 million cells
+		var err error
+		bytes := allocated(func() {
+			_, err = PolygonToCellsContext(context.Background(), NewGeoPolygon(NewGeofence(sfVerts)), 13, Options{MaxMemory: 1 << 20})
+		})
+		require.Equal(t, ErrMemoryLimit, err)
+		require.True(t, bytes < 1<<20, "allocated %d bytes", bytes)
+	})
+
+	t.Run("sliver", func(t *testing.T) {
+		// a long diagonal sliver, of which the bounding box holds many more
+		// cells than the sliver itself
+		sliver := NewGeoPolygon(NewGeofence([]GeoCoord{{0.5, 0.5}, {0.6, 0.6}, {0.6, 0.6001}}))
+		expected, err := PolygonToCells(sliver, 7)
+		require.NoError(t, err)
+		require.True(t, len(expected) > 1)
+		require.True(t, maxPolyfillSize(&sliver, 7) > 100*len(expected))
+
+		cells, err := PolygonToCellsContext(context.Background(), sliver, 7, Options{MaxCells: len(expected)})
+		require.NoError(t, err)
+		require.ElementsMatch(t, expected, cells)
+		_, err = PolygonToCellsContext(context.Background(), sliver, 7, Options{MaxCells: len(expected) - 1})
+		require.Equal(t, ErrCellLimit, err)
+	})
+}
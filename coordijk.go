@@ -525,8 +525,8 @@ func ijkDistance(c1 *CoordIJK, c2 *CoordIJK) float64 {
  * @param ij The output IJ coordinates
  */
 func ijkToIj(ijk *CoordIJK, ij *CoordIJ) {
-	ij.i = ijk.i - ijk.k
-	ij.j = ijk.j - ijk.k
+	ij.I = ijk.i - ijk.k
+	ij.J = ijk.j - ijk.k
 }
 
 /**
@@ -537,8 +537,8 @@ func ijkToIj(ijk *CoordIJK, ij *CoordIJ) {
  * @param ijk The output IJK+ coordinates
  */
 func ijToIjk(ij *CoordIJ, ijk *CoordIJK) {
-	ijk.i = ij.i
-	ijk.j = ij.j
+	ijk.i = ij.I
+	ijk.j = ij.J
 	ijk.k = 0
 	_ijkNormalize(ijk)
 }
@@ -36,8 +36,8 @@ func Test_CoordIJ(t *testing.T) {
 		ijk := CoordIJK{}
 		ij := CoordIJ{}
 		ijkToIj(&ijk, &ij)
-		require.True(t, ij.i == 0, "ij.i zero")
-		require.True(t, ij.j == 0, "ij.j zero")
+		require.True(t, ij.I == 0, "ij.I zero")
+		require.True(t, ij.J == 0, "ij.J zero")
 		ijToIjk(&ij, &ijk)
 		require.True(t, ijk.i == 0, "ijk.i zero")
 		require.True(t, ijk.j == 0, "ijk.j zero")
@@ -0,0 +1,93 @@
+package h3
+
+import (
+	"errors"
+	"fmt"
+)
+
+/** The input is not a valid H3 cell index. */
+var ErrInvalidIndex = errors.New("h3: invalid index")
+
+/** An argument is outside of its domain, such as a negative k. */
+var ErrDomain = errors.New("h3: argument out of domain")
+
+/** The input cells are not all of the same resolution. */
+var ErrResolutionMismatch = errors.New("h3: resolution mismatch")
+
+/** The operation could not be completed for otherwise valid input. */
+var ErrFailed = errors.New("h3: operation failed")
+
+/** Pentagonal distortion was encountered which the algorithm can not handle. */
+var ErrPentagon = errors.New("h3: pentagon distortion encountered")
+
+/** The input contains the same cell more than once. */
+var ErrDuplicateInput = errors.New("h3: duplicate input")
+
+/** The cells are not neighbors. */
+var ErrNotNeighbors = errors.New("h3: cells are not neighbors")
+
+/** The cells are under different base cells. */
+var ErrNoCommonAncestor = errors.New("h3: cells have no common ancestor")
+
+/** Encoded cells are corrupt or truncated. */
+var ErrInvalidEncoding = errors.New("h3: invalid cell encoding")
+
+/** The mode of the index is not H3_HEXAGON_MODE. */
+var ErrInvalidMode = fmt.Errorf("%w: wrong mode", ErrInvalidIndex)
+
+/** The base cell of the index is not one of the 122 base cells. */
+var ErrInvalidBaseCell = fmt.Errorf("%w: base cell out of range", ErrInvalidIndex)
+
+/** The resolution of the index is out of range. */
+var ErrInvalidResolution = fmt.Errorf("%w: resolution out of range", ErrInvalidIndex)
+
+/** The index is in the deleted K axes subsequence of a pentagon. */
+var ErrDeletedSubsequence = fmt.Errorf("%w: deleted pentagon subsequence", ErrInvalidIndex)
+
+/** A digit within the resolution of the index is not a direction. */
+var ErrInvalidDigit = fmt.Errorf("%w: invalid digit", ErrInvalidIndex)
+
+/** A digit beyond the resolution of the index is not 7. */
+var ErrInvalidUnusedDigit = fmt.Errorf("%w: unused digit is not 7", ErrInvalidIndex)
+
+/** The mode of the edge is not H3_UNIEDGE_MODE. */
+var ErrInvalidEdgeMode = fmt.Errorf("%w: wrong edge mode", ErrInvalidIndex)
+
+/** The direction of the edge is not a neighbor direction of its origin. */
+var ErrInvalidEdgeDirection = fmt.Errorf("%w: invalid edge direction", ErrInvalidIndex)
+
+/** The polygon is not a valid simple polygon with holes. */
+var ErrInvalidPolygon = errors.New("h3: invalid polygon")
+
+/** A loop has fewer than 3 distinct vertices, so it does not enclose an area. */
+var ErrTooFewVertices = fmt.Errorf("%w: too few vertices", ErrInvalidPolygon)
+
+/** A vertex is not finite or its latitude is beyond a pole. */
+var ErrInvalidVertex = fmt.Errorf("%w: invalid vertex", ErrInvalidPolygon)
+
+/** The last vertex of a loop repeats the first, which closes it twice. */
+var ErrClosingVertex = fmt.Errorf("%w: repeated closing vertex", ErrInvalidPolygon)
+
+/** An edge joins a vertex to itself. */
+var ErrDegenerateEdge = fmt.Errorf("%w: degenerate edge", ErrInvalidPolygon)
+
+/** Two edges of the polygon cross or touch. */
+var ErrSelfIntersection = fmt.Errorf("%w: self intersection", ErrInvalidPolygon)
+
+/** A hole is not inside the exterior boundary. */
+var ErrHoleOutside = fmt.Errorf("%w: hole outside of the geofence", ErrInvalidPolygon)
+
+/** A hole is inside another hole. */
+var ErrNestedHole = fmt.Errorf("%w: nested hole", ErrInvalidPolygon)
+
+/** The geofence is clockwise or a hole is counter-clockwise. */
+var ErrWrongOrientation = fmt.Errorf("%w: wrong orientation", ErrInvalidPolygon)
+
+/** An operation would exceed the limits of its Options. */
+var ErrLimitExceeded = errors.New("h3: limit exceeded")
+
+/** An operation would output more than MaxCells cells. */
+var ErrCellLimit = fmt.Errorf("%w: too many cells", ErrLimitExceeded)
+
+/** An operation would allocate more than MaxMemory bytes. */
+var ErrMemoryLimit = fmt.Errorf("%w: too much memory", ErrLimitExceeded)
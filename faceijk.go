@@ -483,9 +483,11 @@ func _faceIjkToGeo(h *FaceIJK, res int, g *GeoCoord) {
  *
  * @param h The address *FaceIJK of the pentagonal cell.
  * @param res The H3 resolution of the cell.
+ * @param start The first topological vertex to return.
+ * @param length The number of topological vertexes to return.
  * @param g The spherical coordinates of the cell boundary.
  */
-func _faceIjkPentToGeoBoundary(h *FaceIJK, res int, g *GeoBoundary) {
+func _faceIjkPentToGeoBoundary(h *FaceIJK, res int, start int, length int, g *GeoBoundary) {
 	adjRes := res
 	centerIJK := *h
 	fijkVerts := make([]FaceIJK, NUM_PENT_VERTS)
@@ -495,10 +497,17 @@ func _faceIjkPentToGeoBoundary(h *FaceIJK, res int, g *GeoBoundary) {
 	// convert each vertex to Lat/Lon
 	// adjust the face of each vertex as appropriate and introduce
 	// edge-crossing vertices as needed
+	// If we're returning the entire loop, we need one more iteration in case
+	// of a distortion vertex on the last edge
+	additionalIteration := 0
+	if length == NUM_PENT_VERTS {
+		additionalIteration = 1
+	}
+
 	g.numVerts = 0
 	var lastFijk *FaceIJK
 
-	for vert := 0; vert < NUM_PENT_VERTS+1; vert++ {
+	for vert := start; vert < start+length+additionalIteration; vert++ {
 
 		v := vert % NUM_PENT_VERTS
 		fijk := fijkVerts[v]
@@ -508,7 +517,7 @@ func _faceIjkPentToGeoBoundary(h *FaceIJK, res int, g *GeoBoundary) {
 		// all Class III pentagon edges cross icosa edges
 		// note that Class II pentagons have vertices on the edge,
 		// not edge intersections
-		if isResClassIII(res) && vert > 0 {
+		if isResClassIII(res) && vert > start {
 			// find hex2d of the two vertexes on the last face
 
 			tmpFijk := fijk
@@ -576,9 +585,9 @@ func _faceIjkPentToGeoBoundary(h *FaceIJK, res int, g *GeoBoundary) {
 		}
 
 		// convert vertex to Lat/Lon and add to the result
-		// vert == NUM_PENT_VERTS is only used to test for possible intersection
-		// on last edge
-		if vert < NUM_PENT_VERTS {
+		// vert == start + NUM_PENT_VERTS is only used to test for possible
+		// intersection on last edge
+		if vert < start+NUM_PENT_VERTS {
 			var vec Vec2d
 			_ijkToHex2d(&fijk.coord, &vec)
 
@@ -664,11 +673,13 @@ func _faceIjkPentToVerts(fijk *FaceIJK, res *int, fijkVerts []FaceIJK) {
  * @param h The address *FaceIJK of the cell.
  * @param res The H3 resolution of the cell.
  * @param isPentagon Whether or not the cell is a pentagon.
+ * @param start The first topological vertex to return.
+ * @param length The number of topological vertexes to return.
  * @param g The spherical coordinates of the cell boundary.
  */
-func _faceIjkToGeoBoundary(h *FaceIJK, res int, isPentagon bool, g *GeoBoundary) {
+func _faceIjkToGeoBoundary(h *FaceIJK, res int, isPentagon bool, start int, length int, g *GeoBoundary) {
 	if isPentagon {
-		_faceIjkPentToGeoBoundary(h, res, g)
+		_faceIjkPentToGeoBoundary(h, res, start, length, g)
 		return
 	}
 
@@ -683,11 +694,18 @@ func _faceIjkToGeoBoundary(h *FaceIJK, res int, isPentagon bool, g *GeoBoundary)
 	// convert each vertex to Lat/Lon
 	// adjust the face of each vertex as appropriate and introduce
 	// edge-crossing vertices as needed
+	// If we're returning the entire loop, we need one more iteration in case
+	// of a distortion vertex on the last edge
+	additionalIteration := 0
+	if length == NUM_HEX_VERTS {
+		additionalIteration = 1
+	}
+
 	g.numVerts = 0
 	lastFace := -1
 	lastOverage := NO_OVERAGE
 
-	for vert := 0; vert < NUM_HEX_VERTS+1; vert++ {
+	for vert := start; vert < start+length+additionalIteration; vert++ {
 		v := vert % NUM_HEX_VERTS
 
 		fijk := fijkVerts[v]
@@ -704,7 +722,7 @@ func _faceIjkToGeoBoundary(h *FaceIJK, res int, isPentagon bool, g *GeoBoundary)
 		   projection. Note that Class II cell edges have vertices on the face
 		   edge, with no edge line intersections.
 		*/
-		if isResClassIII(res) && vert > 0 && fijk.face != lastFace &&
+		if isResClassIII(res) && vert > start && fijk.face != lastFace &&
 			lastOverage != FACE_EDGE {
 			// find hex2d of the two vertexes on original face
 			lastV := (v + 5) % NUM_HEX_VERTS
@@ -769,9 +787,9 @@ func _faceIjkToGeoBoundary(h *FaceIJK, res int, isPentagon bool, g *GeoBoundary)
 		}
 
 		// convert vertex to Lat/Lon and add to the result
-		// vert == NUM_HEX_VERTS is only used to test for possible intersection
-		// on last edge
-		if vert < NUM_HEX_VERTS {
+		// vert == start + NUM_HEX_VERTS is only used to test for possible
+		// intersection on last edge
+		if vert < start+NUM_HEX_VERTS {
 			var vec Vec2d
 			_ijkToHex2d(&fijk.coord, &vec)
 			if len(g.Verts) == g.numVerts {
@@ -0,0 +1,131 @@
+//go:build gofuzz
+// +build gofuzz
+
+package h3
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+/*
+ * Fuzz targets for go-fuzz. Each target decodes its arguments from the fuzz
+ * input, calls the exported functions and panics when a result contradicts
+ * the returned error. Run one with
+ *
+ *     go-fuzz-build -func FuzzGeoToCell && go-fuzz
+ *
+ * The targets return 1 for inputs that get past validation, so go-fuzz
+ * favors them, and 0 otherwise.
+ */
+
+/**
+ * fuzzUint64 returns the i-th 8 byte word of the input, zero padded.
+ */
+func fuzzUint64(data []byte, i int) uint64 {
+	var word [8]byte
+	if i*8 < len(data) {
+		copy(word[:], data[i*8:])
+	}
+	return binary.LittleEndian.Uint64(word[:])
+}
+
+func fuzzIndex(data []byte, i int) H3Index {
+	return H3Index(fuzzUint64(data, i))
+}
+
+func fuzzFloat(data []byte, i int) float64 {
+	return math.Float64frombits(fuzzUint64(data, i))
+}
+
+/**
+ * fuzzInt returns the i-th word as a small signed integer, with the range
+ * chosen to include invalid values.
+ */
+func fuzzInt(data []byte, i int, max int) int {
+	return int(int16(fuzzUint64(data, i))) % max
+}
+
+func FuzzGeoToCell(data []byte) int {
+	g := GeoCoord{Lat: fuzzFloat(data, 0), Lon: fuzzFloat(data, 1)}
+	h, err := GeoToCell(g, fuzzInt(data, 2, 32))
+	if err != nil {
+		return 0
+	}
+	if err := Validate(h); err != nil {
+		panic(err)
+	}
+	if _, err := CellToGeo(h); err != nil {
+		panic(err)
+	}
+	return 1
+}
+
+func FuzzCellToBoundary(data []byte) int {
+	gb, err := CellToBoundary(fuzzIndex(data, 0))
+	if err != nil {
+		return 0
+	}
+	if len(gb.Verts) < NUM_PENT_VERTS || len(gb.Verts) > MAX_CELL_BNDRY_VERTS {
+		panic("boundary vertex count out of range")
+	}
+	return 1
+}
+
+func FuzzGridDisk(data []byte) int {
+	origin := fuzzIndex(data, 0)
+	k := fuzzInt(data, 1, 64)
+	disk, distances, err := GridDiskDistances(origin, k)
+	if err != nil {
+		return 0
+	}
+	if len(disk) == 0 || disk[0] != origin || len(distances) != len(disk) {
+		panic("disk does not start with the origin")
+	}
+	if _, err := GridRing(origin, k); err != nil {
+		panic(err)
+	}
+	return 1
+}
+
+func FuzzCompactCells(data []byte) int {
+	cells := make([]H3Index, len(data)/8)
+	for i := range cells {
+		cells[i] = fuzzIndex(data, i)
+	}
+	compacted, err := CompactCells(cells)
+	if err != nil || len(cells) == 0 {
+		return 0
+	}
+	uncompacted, err := UncompactCells(compacted, H3_GET_RESOLUTION(cells[0]))
+	if err != nil {
+		panic(err)
+	}
+	if len(uncompacted) != len(cells) {
+		panic("uncompact does not restore the cells")
+	}
+	return 1
+}
+
+func FuzzDirectedEdge(data []byte) int {
+	origin, destination := fuzzIndex(data, 0), fuzzIndex(data, 1)
+	DirectedEdgeToCells(origin)
+	DirectedEdgeToBoundary(origin)
+	OriginToDirectedEdges(origin)
+
+	edge, err := CellsToDirectedEdge(origin, destination)
+	if err != nil {
+		return 0
+	}
+	if err := ValidateDirectedEdge(edge); err != nil {
+		panic(err)
+	}
+	o, d, err := DirectedEdgeToCells(edge)
+	if err != nil || o != origin || d != destination {
+		panic("edge does not join its cells")
+	}
+	if _, err := DirectedEdgeToBoundary(edge); err != nil {
+		panic(err)
+	}
+	return 1
+}
@@ -0,0 +1,120 @@
+package h3
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+/**
+ * malformedIndex returns either random bits or a valid cell with one of its
+ * fields corrupted, the latter being far more likely to reach deep into the
+ * library.
+ */
+func malformedIndex(r *rand.Rand) H3Index {
+	g := GeoCoord{Lat: (r.Float64() - 0.5) * math.Pi, Lon: (r.Float64()*2 - 1) * math.Pi}
+	h := geoToH3(&g, r.Intn(MAX_H3_RES+1))
+
+	switch r.Intn(8) {
+	case 0:
+		return H3Index(r.Uint64())
+	case 1:
+		H3_SET_MODE(&h, H3Mode(r.Intn(16)))
+	case 2:
+		H3_SET_BASE_CELL(&h, r.Intn(128))
+	case 3:
+		H3_SET_RESOLUTION(&h, r.Intn(16))
+	case 4:
+		H3_SET_INDEX_DIGIT(&h, 1+r.Intn(MAX_H3_RES), Direction(r.Intn(8)))
+	case 5:
+		H3_SET_MODE(&h, H3_UNIEDGE_MODE)
+		H3_SET_RESERVED_BITS(&h, r.Intn(8))
+	case 6:
+		h ^= 1 << uint(r.Intn(64))
+	}
+	return h
+}
+
+func malformedFloat(r *rand.Rand) float64 {
+	switch r.Intn(8) {
+	case 0:
+		return math.NaN()
+	case 1:
+		return math.Inf(1 - 2*r.Intn(2))
+	case 2:
+		return (r.Float64() - 0.5) * 1e300
+	}
+	return (r.Float64() - 0.5) * 8
+}
+
+func malformedInt(r *rand.Rand) int {
+	switch r.Intn(6) {
+	case 0:
+		return -1 - r.Intn(100)
+	case 1:
+		return math.MaxInt32 + r.Intn(100)
+	case 2:
+		return 16 + r.Intn(100)
+	}
+	return r.Intn(16)
+}
+
+func TestMalformedInput(t *testing.T) {
+	r := rand.New(rand.NewSource(34))
+
+	for i := 0; i < 2000; i++ {
+		// b is mostly a corrupted copy of a, which makes neighbors and
+		// cells of the same resolution likely
+		a, b := malformedIndex(r), malformedIndex(r)
+		if r.Intn(8) != 0 {
+			b = a ^ 1<<uint(r.Intn(64))
+		}
+		g := GeoCoord{Lat: malformedFloat(r), Lon: malformedFloat(r)}
+		n := malformedInt(r)
+		// Corrupting valid bits never makes a valid coarser cell, which
+		// keeps uncompacting the set cheap
+		set := []H3Index{a, a ^ 1<<uint(r.Intn(64)), a ^ 1<<uint(r.Intn(64))}
+		res := n
+		if res >= 0 && res <= MAX_H3_RES {
+			res = H3_GET_RESOLUTION(a) + n%3
+		}
+		polygon := NewGeoPolygon(NewGeofence([]GeoCoord{
+			{malformedFloat(r), malformedFloat(r)},
+			{malformedFloat(r), malformedFloat(r)},
+			{malformedFloat(r), malformedFloat(r)},
+		}))
+
+		require.NotPanics(t, func() {
+			GeoToCell(g, n)
+			CellToGeo(a)
+			CellToBoundary(a)
+			CellCenterDistance(a, b, UNIT_KM)
+			GridDisk(a, n%64)
+			GridRing(a, n%64)
+			CompactCells(set)
+			UncompactCells(set, res)
+			PolygonToCells(polygon, n%4)
+			CellsToMultiPolygon(set)
+			AreNeighborCells(a, b)
+			CellsToDirectedEdge(a, b)
+			DirectedEdgeToCells(a)
+			OriginToDirectedEdges(a)
+			DirectedEdgeToBoundary(a)
+			CellToLocalIJ(a, b)
+			LocalIJToCell(a, CoordIJ{I: n, J: malformedInt(r)})
+			if H3_GET_RESOLUTION(a) <= 5 {
+				// paths between distant cells at fine resolutions are slow
+				GridDistance(a, b)
+			}
+			Validate(a)
+			ValidateDirectedEdge(a)
+			_ = Inspect(a).String()
+			H3_GET_INDEX_DIGIT(a, n)
+			c := a
+			H3_SET_INDEX_DIGIT(&c, n, K_AXES_DIGIT)
+			NewRaster(a, n%8, RasterLayout(n%3))
+		}, "inputs %x %x %v %d", a, b, g, n)
+	}
+}
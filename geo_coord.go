@@ -58,6 +58,16 @@ func geoAlmostEqual(p1 *GeoCoord, p2 *GeoCoord) bool {
 	return geoAlmostEqualThreshold(p1, p2, EPSILON_RAD)
 }
 
+/**
+ * Determines if both components of spherical coordinates are finite numbers.
+ *
+ * @param g The spherical coordinates.
+ * @return Whether neither component is NaN or infinite.
+ */
+func _geoIsFinite(g *GeoCoord) bool {
+	return !math.IsNaN(g.Lat) && !math.IsNaN(g.Lon) && !math.IsInf(g.Lat, 0) && !math.IsInf(g.Lon, 0)
+}
+
 /**
  * Set the components of spherical coordinates in decimal degrees.
  *
@@ -178,6 +188,36 @@ func _geoDistKm(p1 *GeoCoord, p2 *GeoCoord) float64 {
 	return EARTH_RADIUS_KM * _geoDistRads(p1, p2)
 }
 
+/**
+ * Find the great circle distance in radians between a point and the shorter
+ * great circle arc between two points.
+ *
+ * @param p The point.
+ * @param a The start of the arc.
+ * @param b The end of the arc.
+ * @return The distance in radians from p to the nearest point of the arc.
+ */
+func _geoArcDistRads(p *GeoCoord, a *GeoCoord, b *GeoCoord) float64 {
+	var vp, va, vb, normal, side Vec3d
+	_geoToVec3d(p, &vp)
+	_geoToVec3d(a, &va)
+	_geoToVec3d(b, &vb)
+	_vec3dCross(&va, &vb, &normal)
+
+	// the nearest point of the great circle is on the arc when p is on the
+	// inner side of both ends
+	if norm := math.Sqrt(_vec3dDot(&normal, &normal)); norm > 0 {
+		_vec3dCross(&va, &vp, &side)
+		if _vec3dDot(&side, &normal) >= 0 {
+			_vec3dCross(&vp, &vb, &side)
+			if _vec3dDot(&side, &normal) >= 0 {
+				return math.Asin(math.Min(1, math.Abs(_vec3dDot(&vp, &normal))/norm))
+			}
+		}
+	}
+	return math.Min(_geoDistRads(p, a), _geoDistRads(p, b))
+}
+
 /**
  * Determines the azimuth to p2 from p1 in radians.
  *
@@ -272,6 +312,114 @@ func _geoAzDistanceRads(p1 *GeoCoord, az float64, distance float64, p2 *GeoCoord
 	}
 }
 
+/** Unit of length used by the exported distance functions. */
+type DistanceUnit int
+
+/** Distance units */
+const (
+	UNIT_RADS DistanceUnit = iota ///< radians of arc on the unit sphere
+	UNIT_KM                       ///< kilometers on the authalic sphere
+	UNIT_M                        ///< meters on the authalic sphere
+)
+
+/**
+ * Converts a great circle distance in radians to the given unit.
+ *
+ * @param rads The distance in radians.
+ * @param unit The desired unit.
+ * @return The distance in the desired unit, or NaN for an unknown unit.
+ */
+func _radsToUnit(rads float64, unit DistanceUnit) float64 {
+	switch unit {
+	case UNIT_RADS:
+		return rads
+	case UNIT_KM:
+		return rads * EARTH_RADIUS_KM
+	case UNIT_M:
+		return rads * EARTH_RADIUS_KM * 1000
+	}
+	return math.NaN()
+}
+
+/**
+ * Converts a great circle distance in the given unit to radians.
+ *
+ * @param distance The distance in the given unit.
+ * @param unit The unit of distance.
+ * @return The distance in radians, or NaN for an unknown unit.
+ */
+func _unitToRads(distance float64, unit DistanceUnit) float64 {
+	switch unit {
+	case UNIT_RADS:
+		return distance
+	case UNIT_KM:
+		return distance / EARTH_RADIUS_KM
+	case UNIT_M:
+		return distance / (EARTH_RADIUS_KM * 1000)
+	}
+	return math.NaN()
+}
+
+/**
+ * Distance returns the great circle distance between two spherical
+ * coordinates.
+ *
+ * @param a The first spherical coordinates.
+ * @param b The second spherical coordinates.
+ * @param unit The unit of the returned distance.
+ * @return The distance between a and b, or NaN for an unknown unit.
+ */
+func Distance(a GeoCoord, b GeoCoord, unit DistanceUnit) float64 {
+	return _radsToUnit(_geoDistRads(&a, &b), unit)
+}
+
+/**
+ * Bearing returns the initial bearing (forward azimuth) from a to b.
+ *
+ * @param a The origin spherical coordinates.
+ * @param b The target spherical coordinates.
+ * @return The bearing in radians clockwise from north, in [0, 2pi).
+ */
+func Bearing(a GeoCoord, b GeoCoord) float64 {
+	return _posAngleRads(_geoAzimuthRads(&a, &b))
+}
+
+/**
+ * Destination returns the point reached by travelling along a great circle
+ * from origin with the given initial bearing for the given distance.
+ *
+ * @param origin The origin spherical coordinates.
+ * @param bearing The initial bearing in radians clockwise from north.
+ * @param distance The distance to travel, must be non-negative.
+ * @param unit The unit of distance.
+ * @return The destination spherical coordinates.
+ */
+func Destination(origin GeoCoord, bearing float64, distance float64, unit DistanceUnit) GeoCoord {
+	var out GeoCoord
+	_geoAzDistanceRads(&origin, bearing, _unitToRads(distance, unit), &out)
+	return out
+}
+
+/**
+ * CellCenterDistance returns the great circle distance between the centers
+ * of two H3 cells. The cells may be of different resolutions.
+ *
+ * @param a The first H3 index.
+ * @param b The second H3 index.
+ * @param unit The unit of the returned distance.
+ * @return The distance between the cell centers.
+ */
+func CellCenterDistance(a H3Index, b H3Index, unit DistanceUnit) (float64, error) {
+	if !h3IsValid(a) || !h3IsValid(b) {
+		return 0, ErrInvalidIndex
+	}
+
+	var ga, gb GeoCoord
+	h3ToGeo(a, &ga)
+	h3ToGeo(b, &gb)
+	return Distance(ga, gb, unit), nil
+}
+
 /*
  * The following functions provide meta information about the H3 hexagons at
  * each zoom level. Since there are only 16 total levels, these are current
@@ -157,3 +157,86 @@ func Test_intConstants(t *testing.T) {
 		last = next
 	}
 }
+
+func TestDistance(t *testing.T) {
+	var p1, p2 GeoCoord
+	setGeoDegs(&p1, 10, 10)
+	setGeoDegs(&p2, 0, 10)
+
+	rads := Distance(p1, p2, UNIT_RADS)
+	require.True(t, math.Abs(rads-degsToRads(10)) < EPSILON_RAD*1000, "distance in radians")
+	require.True(t, math.Abs(Distance(p1, p2, UNIT_KM)-rads*EARTH_RADIUS_KM) < 1e-9, "distance in km")
+	require.True(t, math.Abs(Distance(p1, p2, UNIT_M)-rads*EARTH_RADIUS_KM*1000) < 1e-6, "distance in m")
+	require.True(t, math.IsNaN(Distance(p1, p2, DistanceUnit(-1))), "unknown unit")
+}
+
+func TestBearing(t *testing.T) {
+	var origin, north, east, south, west GeoCoord
+	setGeoDegs(&origin, 0, 0)
+	setGeoDegs(&north, 1, 0)
+	setGeoDegs(&east, 0, 1)
+	setGeoDegs(&south, -1, 0)
+	setGeoDegs(&west, 0, -1)
+
+	require.True(t, math.Abs(Bearing(origin, north)) < EPSILON_RAD, "due north")
+	require.True(t, math.Abs(Bearing(origin, east)-M_PI_2) < EPSILON_RAD, "due east")
+	require.True(t, math.Abs(Bearing(origin, south)-M_PI) < EPSILON_RAD, "due south")
+	require.True(t, math.Abs(Bearing(origin, west)-3*M_PI_2) < EPSILON_RAD, "due west is positive")
+}
+
+func TestDestination(t *testing.T) {
+	var start GeoCoord
+	setGeoDegs(&start, 15, 10)
+
+	out := Destination(start, degsToRads(20), 1500, UNIT_KM)
+	require.True(t, math.Abs(Distance(start, out, UNIT_KM)-1500) < 1e-6, "moved distance is as expected")
+	require.True(t, math.Abs(Bearing(start, out)-degsToRads(20)) < 1e-9, "moved bearing is as expected")
+
+	same := Destination(start, degsToRads(20), 0, UNIT_M)
+	require.True(t, geoAlmostEqual(&start, &same), "0 distance produces same point")
+}
+
+func TestCellCenterDistance(t *testing.T) {
+	a := geoToH3(GeoFromWGS84(37.77, -122.42), 9)
+	b := geoToH3(GeoFromWGS84(37.80, -122.27), 9)
+
+	var ga, gb GeoCoord
+	h3ToGeo(a, &ga)
+	h3ToGeo(b, &gb)
+
+	same, err := CellCenterDistance(a, a, UNIT_M)
+	require.NoError(t, err)
+	require.True(t, same < 1e-6, "same cell")
+
+	d, err := CellCenterDistance(a, b, UNIT_KM)
+	require.NoError(t, err)
+	require.Equal(t, _geoDistKm(&ga, &gb), d)
+
+	_, err = CellCenterDistance(a, 0, UNIT_KM)
+	require.Equal(t, ErrInvalidIndex, err, "invalid cell")
+}
+
+func Test__geoArcDistRads(t *testing.T) {
+	var a, b, p GeoCoord
+	setGeoDegs(&a, 0, 0)
+	setGeoDegs(&b, 0, 20)
+
+	setGeoDegs(&p, 5, 10)
+	require.True(t, math.Abs(_geoArcDistRads(&p, &a, &b)-degsToRads(5)) < EPSILON_RAD, "above the arc")
+	require.True(t, math.Abs(_geoArcDistRads(&p, &b, &a)-degsToRads(5)) < EPSILON_RAD, "reversed arc")
+	setGeoDegs(&p, -5, 10)
+	require.True(t, math.Abs(_geoArcDistRads(&p, &a, &b)-degsToRads(5)) < EPSILON_RAD, "below the arc")
+	setGeoDegs(&p, 0, 30)
+	require.True(t, math.Abs(_geoArcDistRads(&p, &a, &b)-degsToRads(10)) < EPSILON_RAD, "beyond the end")
+	setGeoDegs(&p, 0, 190)
+	require.True(t, math.Abs(_geoArcDistRads(&p, &a, &b)-degsToRads(170)) < EPSILON_RAD, "opposite the arc")
+	require.True(t, math.Abs(_geoArcDistRads(&p, &a, &a)-degsToRads(170)) < EPSILON_RAD, "degenerate arc")
+}
+
+func Test_geoIsFinite(t *testing.T) {
+	require.True(t, _geoIsFinite(&GeoCoord{Lat: 0.5, Lon: -3}))
+	require.False(t, _geoIsFinite(&GeoCoord{Lat: math.NaN()}))
+	require.False(t, _geoIsFinite(&GeoCoord{Lon: math.NaN()}))
+	require.False(t, _geoIsFinite(&GeoCoord{Lat: math.Inf(-1)}))
+	require.False(t, _geoIsFinite(&GeoCoord{Lon: math.Inf(1)}))
+}
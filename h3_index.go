@@ -2,7 +2,10 @@ package h3
 
 import (
 	"math"
+	"runtime"
 	"strconv"
+	"sync"
+	"sync/atomic"
 )
 
 type H3Index uint64
@@ -106,10 +109,20 @@ func H3_SET_RESOLUTION(h3 *H3Index, res int) {
 }
 
 /**
- * Gets the resolution res integer digit (0-7) of h3.
+ * Gets the resolution res integer digit (0-7) of h3. Resolutions above 15
+ * read as 0.
  */
 func H3_GET_INDEX_DIGIT(h3 H3Index, res int) Direction {
-	return Direction((uint64(h3) >> ((MAX_H3_RES - (res)) * H3_PER_DIGIT_OFFSET)) & uint64(H3_DIGIT_MASK))
+	return Direction((uint64(h3) >> _digitOffset(res)) & uint64(H3_DIGIT_MASK))
+}
+
+/**
+ * _digitOffset returns the bit offset of the resolution res digit. It is
+ * unsigned so that resolutions above 15 shift every bit out instead of
+ * panicking on a negative shift count.
+ */
+func _digitOffset(res int) uint {
+	return uint((MAX_H3_RES - res) * H3_PER_DIGIT_OFFSET)
 }
 
 /**
@@ -128,10 +141,11 @@ func H3_GET_RESERVED_BITS(h3 H3Index) int {
 }
 
 /**
- * Sets the resolution res digit of h3 to the integer digit (0-7)
+ * Sets the resolution res digit of h3 to the integer digit (0-7). Resolutions
+ * above 15 leave h3 unchanged.
  */
 func H3_SET_INDEX_DIGIT(h3 *H3Index, res int, digit Direction) {
-	*h3 = H3Index((uint64(*h3) & ^(uint64(H3_DIGIT_MASK) << ((MAX_H3_RES - (res)) * H3_PER_DIGIT_OFFSET))) | (uint64(digit) << ((MAX_H3_RES - (res)) * H3_PER_DIGIT_OFFSET)))
+	*h3 = H3Index((uint64(*h3) & ^(uint64(H3_DIGIT_MASK) << _digitOffset(res))) | (uint64(digit) << _digitOffset(res)))
 }
 
 /**
@@ -296,6 +310,24 @@ func maxH3ToChildrenSize(h H3Index, childRes int) int {
 	return _ipow(7, childRes-parentRes)
 }
 
+/**
+ * _numChildren returns the number of children of a cell at a resolution,
+ * which is less than maxH3ToChildrenSize for pentagons.
+ *
+ * @param h The cell.
+ * @param childRes The resolution of the children.
+ * @return The number of children.
+ */
+func _numChildren(h H3Index, childRes int) int {
+	n := maxH3ToChildrenSize(h, childRes)
+	if h3IsPentagon(h) && n > 1 {
+		// the center child is a pentagon again, and the five others are
+		// hexagons
+		return 1 + 5*(n-1)/6
+	}
+	return n
+}
+
 /**
  * makeDirectChild takes an index and immediately returns the immediate child
  * index based on the specified cell number. Bit operations only, could generate
@@ -341,8 +373,9 @@ func h3ToChildren(h H3Index, childRes int, children *[]H3Index) {
 
 	for i := Direction(0); i < 7; i++ {
 		if isAPentagon && i == K_AXES_DIGIT {
-			nextChild := make([]H3Index, bufferChildStep)
-			for len(*children) < len(nextChild) {
+			// pad the deleted subsequence so that the children keep their
+			// position in the buffer
+			for j := 0; j < bufferChildStep; j++ {
 				*children = append(*children, H3_INVALID_INDEX)
 			}
 		} else {
@@ -453,7 +486,7 @@ func compact(h3Set []H3Index, compactedSet []H3Index, numHexes int) int {
 		compactableCount := 0
 		maxCompactableCount := numRemainingHexes / 6 // Somehow all pentagons; conservative
 		if maxCompactableCount == 0 {
-			copy(compactedSetOffset, remainingHexes)
+			copy(compactedSetOffset, remainingHexes[:numRemainingHexes])
 			break
 		}
 
@@ -513,9 +546,6 @@ func compact(h3Set []H3Index, compactedSet []H3Index, numHexes int) int {
 						loc = (loc + 1) % numRemainingHexes
 					}
 					loopCount++
-					if hashSetArray[loc] != parent {
-						break
-					}
 				}
 				if isUncompactable {
 					compactedSetOffset[uncompactableCount] = remainingHexes[i]
@@ -524,10 +554,12 @@ func compact(h3Set []H3Index, compactedSet []H3Index, numHexes int) int {
 			}
 		}
 		// Set up for the next loop
-		//memset(hashSetArray, 0, numHexes * sizeof(H3Index));
-		//compactedSetOffset += uncompactableCount;
+		for i := range hashSetArray {
+			hashSetArray[i] = H3_INVALID_INDEX
+		}
+		compactedSetOffset = compactedSetOffset[uncompactableCount:]
 
-		copy(remainingHexes, compactableHexes)
+		copy(remainingHexes, compactableHexes[:compactableCount])
 		numRemainingHexes = compactableCount
 		compactableHexes = nil
 	}
@@ -575,14 +607,337 @@ func uncompact(compactedSet []H3Index, numHexes int, h3Set []H3Index, maxHexes i
 				// We're about to go too far, abort!
 				return -1
 			}
-			// todo fix
-			//h3ToChildren(compactedSet[i], res, h3Set+outOffset)
+			children := h3Set[outOffset:outOffset]
+			h3ToChildren(compactedSet[i], res, &children)
 			outOffset += numHexesToGen
 		}
 	}
 	return 0
 }
 
+/**
+ * CompactCells compresses a set of cells of a single resolution by replacing
+ * complete sets of siblings with their parent, recursively.
+ *
+ * @param h3Set Cells of a single resolution, without duplicates.
+ * @return The compacted set.
+ */
+func CompactCells(h3Set []H3Index) ([]H3Index, error) {
+	return CompactCellsInPlace(append([]H3Index{}, h3Set...))
+}
+
+/**
+ * CompactCellsInPlace compacts a set of cells like CompactCells, reusing the
+ * memory of the input. The cells are sorted, which brings siblings next to
+ * each other, and each complete run of siblings, seven of them or six under
+ * a pentagon, is replaced by their parent, one resolution at a time. Beyond
+ * the sort, the work is linear in the number of cells.
+ *
+ * @param h3Set Cells of a single resolution, without duplicates. The slice
+ *              is reordered and overwritten.
+ * @return The compacted set, which is a prefix of h3Set.
+ */
+func CompactCellsInPlace(h3Set []H3Index) ([]H3Index, error) {
+	for i := range h3Set {
+		if !h3IsValid(h3Set[i]) {
+			return nil, ErrInvalidIndex
+		}
+		if H3_GET_RESOLUTION(h3Set[i]) != H3_GET_RESOLUTION(h3Set[0]) {
+			return nil, ErrResolutionMismatch
+		}
+	}
+	if len(h3Set) == 0 {
+		return h3Set, nil
+	}
+	_sortCells(h3Set, 0, H3_GET_RESOLUTION(h3Set[0]))
+	for i := 1; i < len(h3Set); i++ {
+		if h3Set[i] == h3Set[i-1] {
+			return nil, ErrDuplicateInput
+		}
+	}
+
+	// Each parent takes the place of its children, so siblings stay next to
+	// each other. Cells of finer resolutions left between two siblings mean
+	// that the run of siblings is not complete anyway.
+	n := len(h3Set)
+	for res := H3_GET_RESOLUTION(h3Set[0]); res > 0; res-- {
+		compacted := false
+		out := 0
+		for i := 0; i < n; {
+			if H3_GET_RESOLUTION(h3Set[i]) != res {
+				h3Set[out] = h3Set[i]
+				out++
+				i++
+				continue
+			}
+
+			parent := h3ToParent(h3Set[i], res-1)
+			j := i + 1
+			for j < n && H3_GET_RESOLUTION(h3Set[j]) == res && h3ToParent(h3Set[j], res-1) == parent {
+				j++
+			}
+			numChildren := 7
+			if h3IsPentagon(parent) {
+				numChildren = 6
+			}
+			if j-i == numChildren {
+				h3Set[out] = parent
+				out++
+				compacted = true
+			} else {
+				out += copy(h3Set[out:], h3Set[i:j])
+			}
+			i = j
+		}
+		n = out
+		if !compacted {
+			break
+		}
+	}
+	return h3Set[:n], nil
+}
+
+/**
+ * _sortCells sorts cells of a single resolution in ascending order, in place
+ * and in linear time. It is an American flag sort on the base cell and then
+ * on the digits: the cells are swapped into their bucket, and each bucket is
+ * sorted on the next digits.
+ *
+ * @param cells Valid cells of resolution res.
+ * @param digit The first digit to sort on, 0 for the base cell.
+ * @param res The resolution of the cells.
+ */
+func _sortCells(cells []H3Index, digit int, res int) {
+	if len(cells) < 32 {
+		for i := 1; i < len(cells); i++ {
+			for j := i; j > 0 && cells[j] < cells[j-1]; j-- {
+				cells[j], cells[j-1] = cells[j-1], cells[j]
+			}
+		}
+		return
+	}
+
+	var ends []int
+	if digit == 0 {
+		var baseCellEnds, next [NUM_BASE_CELLS]int
+		_partitionCells(cells, H3_BC_OFFSET, H3Index(H3_BC_MASK>>H3_BC_OFFSET), baseCellEnds[:], next[:])
+		ends = baseCellEnds[:]
+	} else {
+		// two digits at a time, which halves the passes over the cells
+		last := digit + 1
+		if last > res {
+			last = res
+		}
+		var digitEnds, next [1 << (2 * H3_PER_DIGIT_OFFSET)]int
+		numBuckets := 1 << uint((last-digit+1)*H3_PER_DIGIT_OFFSET)
+		_partitionCells(cells, _digitOffset(last), H3Index(numBuckets-1), digitEnds[:numBuckets], next[:numBuckets])
+		ends = digitEnds[:numBuckets]
+		digit = last
+	}
+	if digit == res {
+		return
+	}
+	start := 0
+	for _, end := range ends {
+		_sortCells(cells[start:end], digit+1, res)
+		start = end
+	}
+}
+
+/**
+ * _partitionCells swaps cells into buckets by a field of their index.
+ *
+ * @param cells The cells.
+ * @param shift The offset of the field.
+ * @param mask The mask of the field, once shifted.
+ * @param ends Output ends of the buckets, one per value of the field.
+ * @param next Scratch space as long as ends.
+ */
+func _partitionCells(cells []H3Index, shift uint, mask H3Index, ends []int, next []int) {
+	for _, h := range cells {
+		ends[h>>shift&mask]++
+	}
+	sum := 0
+	for b := range ends {
+		next[b] = sum
+		sum += ends[b]
+		ends[b] = sum
+	}
+	for b := range ends {
+		for next[b] < ends[b] {
+			// move the cell to its bucket, and carry on with the one it
+			// replaces until one belongs here
+			h := cells[next[b]]
+			for d := int(h >> shift & mask); d != b; d = int(h >> shift & mask) {
+				h, cells[next[d]] = cells[next[d]], h
+				next[d]++
+			}
+			cells[next[b]] = h
+			next[b]++
+		}
+	}
+}
+
+/**
+ * UncompactCells expands a compacted set of cells to a single resolution.
+ *
+ * @param compactedSet Cells of resolution res or coarser.
+ * @param res The resolution to expand to.
+ * @return The cells at resolution res. ErrDomain is returned when there
+ *         would be more than MAX_SLICE_CELLS of them.
+ */
+func UncompactCells(compactedSet []H3Index, res int) ([]H3Index, error) {
+	if err := _validateUncompact(compactedSet, res); err != nil {
+		return nil, err
+	}
+
+	// summed a cell at a time, so that the size can not overflow
+	size := 0
+	for _, h := range compactedSet {
+		if size += maxH3ToChildrenSize(h, res); size > MAX_SLICE_CELLS {
+			return nil, ErrDomain
+		}
+	}
+	out := make([]H3Index, 0, size)
+	for _, h := range compactedSet {
+		out = _appendChildren(out, h, h3IsPentagon(h), res)
+	}
+	return out, nil
+}
+
+/**
+ * AppendUncompactedCells expands a compacted set of cells to a single
+ * resolution, appending the cells to dst as the built-in append does, so
+ * that no room has to be allocated up front. The children of each cell
+ * follow in ascending order, without the deleted subsequences of pentagons.
+ *
+ * @param dst The slice to append to.
+ * @param compactedSet Cells of resolution res or coarser.
+ * @param res The resolution to expand to.
+ * @return The extended slice.
+ */
+func AppendUncompactedCells(dst []H3Index, compactedSet []H3Index, res int) ([]H3Index, error) {
+	if err := _validateUncompact(compactedSet, res); err != nil {
+		return dst, err
+	}
+
+	for _, h := range compactedSet {
+		dst = _appendChildren(dst, h, h3IsPentagon(h), res)
+	}
+	return dst, nil
+}
+
+/**
+ * UncompactCellsParallel expands a compacted set of cells to a single
+ * resolution like UncompactCells, with the cells of the set shared out
+ * between goroutines. The room for the children of each cell is found up
+ * front from maxH3ToChildrenSize, so the output does not depend on the
+ * order in which the goroutines run.
+ *
+ * @param compactedSet Cells of resolution res or coarser.
+ * @param res The resolution to expand to.
+ * @param workers The number of goroutines, or 0 for GOMAXPROCS.
+ * @return The cells at resolution res, in the order of UncompactCells.
+ */
+func UncompactCellsParallel(compactedSet []H3Index, res int, workers int) ([]H3Index, error) {
+	if err := _validateUncompact(compactedSet, res); err != nil {
+		return nil, err
+	}
+	if workers < 0 {
+		return nil, ErrDomain
+	}
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// offsets[i] is where the children of the i-th cell start, checked as
+	// they are summed so that they can not overflow
+	offsets := make([]int, len(compactedSet)+1)
+	for i, h := range compactedSet {
+		offsets[i+1] = offsets[i] + maxH3ToChildrenSize(h, res)
+		if offsets[i+1] > MAX_SLICE_CELLS {
+			return nil, ErrDomain
+		}
+	}
+	out := make([]H3Index, offsets[len(compactedSet)])
+	counts := make([]int, len(compactedSet))
+
+	next := int64(-1)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(compactedSet) {
+					return
+				}
+				h := compactedSet[i]
+				children := _appendChildren(out[offsets[i]:offsets[i]:offsets[i+1]], h, h3IsPentagon(h), res)
+				counts[i] = len(children)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Close the gaps left by the deleted subsequences of pentagons
+	n := 0
+	for i := range compactedSet {
+		if n != offsets[i] {
+			copy(out[n:], out[offsets[i]:offsets[i]+counts[i]])
+		}
+		n += counts[i]
+	}
+	return out[:n], nil
+}
+
+/**
+ * _validateUncompact checks the input of an uncompaction.
+ *
+ * @param compactedSet Cells of resolution res or coarser.
+ * @param res The resolution to expand to.
+ * @return ErrDomain, ErrInvalidIndex or ErrResolutionMismatch, or nil.
+ */
+func _validateUncompact(compactedSet []H3Index, res int) error {
+	if res < 0 || res > MAX_H3_RES {
+		return ErrDomain
+	}
+	for i := range compactedSet {
+		if !h3IsValid(compactedSet[i]) {
+			return ErrInvalidIndex
+		}
+		if H3_GET_RESOLUTION(compactedSet[i]) > res {
+			return ErrResolutionMismatch
+		}
+	}
+	return nil
+}
+
+/**
+ * _appendChildren appends the children of a cell at a resolution, skipping
+ * the deleted subsequences of pentagons.
+ *
+ * @param dst The slice to append to.
+ * @param h The cell.
+ * @param isPentagon Whether the cell is a pentagon.
+ * @param res The resolution of the children.
+ * @return The extended slice.
+ */
+func _appendChildren(dst []H3Index, h H3Index, isPentagon bool, res int) []H3Index {
+	if H3_GET_RESOLUTION(h) == res {
+		return append(dst, h)
+	}
+	for d := CENTER_DIGIT; d < NUM_DIGITS; d++ {
+		if isPentagon && d == K_AXES_DIGIT {
+			continue
+		}
+		// only the center child of a pentagon is a pentagon
+		dst = _appendChildren(dst, makeDirectChild(h, d), isPentagon && d == CENTER_DIGIT, res)
+	}
+	return dst
+}
+
 /**
 * maxUncompactSize takes a compacted set of hexagons are provides an
 * upper-bound estimate of the size of the uncompacted set of hexagons.
@@ -851,6 +1206,28 @@ func geoToH3(g *GeoCoord, res int) H3Index {
 	return _faceIjkToH3(&fijk, res)
 }
 
+/**
+ * GeoToCell indexes the location at the specified resolution.
+ *
+ * @param g The location in radians.
+ * @param res The resolution (0-15).
+ * @return The cell containing the location.
+ */
+func GeoToCell(g GeoCoord, res int) (H3Index, error) {
+	if res < 0 || res > MAX_H3_RES {
+		return H3_INVALID_INDEX, ErrDomain
+	}
+	if !_geoIsFinite(&g) {
+		return H3_INVALID_INDEX, ErrDomain
+	}
+
+	h := geoToH3(&g, res)
+	if h == H3_INVALID_INDEX {
+		return H3_INVALID_INDEX, ErrFailed
+	}
+	return h, nil
+}
+
 /**
 * Convert an to H3Index the FaceIJK address on a specified icosahedral face.
 * @param h The H3Index.
@@ -962,7 +1339,44 @@ func h3ToGeo(h3 H3Index, g *GeoCoord) {
 func h3ToGeoBoundary(h3 H3Index, gb *GeoBoundary) {
 	var fijk FaceIJK
 	_h3ToFaceIjk(h3, &fijk)
-	_faceIjkToGeoBoundary(&fijk, H3_GET_RESOLUTION(h3), h3IsPentagon(h3), gb)
+	if h3IsPentagon(h3) {
+		_faceIjkToGeoBoundary(&fijk, H3_GET_RESOLUTION(h3), true, 0, NUM_PENT_VERTS, gb)
+	} else {
+		_faceIjkToGeoBoundary(&fijk, H3_GET_RESOLUTION(h3), false, 0, NUM_HEX_VERTS, gb)
+	}
+}
+
+/**
+ * CellToGeo determines the center of a cell.
+ *
+ * @param h The cell.
+ * @return The center in radians.
+ */
+func CellToGeo(h H3Index) (GeoCoord, error) {
+	if !h3IsValid(h) {
+		return GeoCoord{}, ErrInvalidIndex
+	}
+
+	var g GeoCoord
+	h3ToGeo(h, &g)
+	return g, nil
+}
+
+/**
+ * CellToBoundary determines the boundary of a cell.
+ *
+ * @param h The cell.
+ * @return The boundary in radians, vertices in ccw order.
+ */
+func CellToBoundary(h H3Index) (GeoBoundary, error) {
+	if !h3IsValid(h) {
+		return GeoBoundary{}, ErrInvalidIndex
+	}
+
+	var gb GeoBoundary
+	h3ToGeoBoundary(h, &gb)
+	gb.Verts = gb.Verts[:gb.numVerts]
+	return gb, nil
 }
 
 /**
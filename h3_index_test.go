@@ -2,6 +2,8 @@ package h3
 
 import (
 	"math"
+	"math/rand"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -458,3 +460,311 @@ func Test_maxH3ToChildrenSize(t *testing.T) {
 	require.True(t, maxH3ToChildrenSize(parent, 8) == 7, "got expected size for child res")
 	require.True(t, maxH3ToChildrenSize(parent, 9) == 7*7, "got expected size for grandchild res")
 }
+
+func Test_compact(t *testing.T) {
+	t.Run("roundTrip", func(t *testing.T) {
+		parent := H3Index(0x85283473fffffff)
+		children := make([]H3Index, 0)
+		h3ToChildren(parent, 7, &children)
+
+		disk, err := GridDisk(h3ToParent(0x8928308280fffff, 7), 3)
+		require.NoError(t, err)
+
+		set := children
+		for _, h := range disk {
+			if h3ToParent(h, 5) != parent {
+				set = append(set, h)
+			}
+		}
+
+		compacted := make([]H3Index, len(set))
+		require.Equal(t, 0, compact(set, compacted, len(set)))
+		require.Contains(t, compacted, parent)
+
+		size := maxUncompactSize(compacted, len(compacted), 7)
+		require.Equal(t, len(set), size)
+
+		uncompacted := make([]H3Index, size)
+		require.Equal(t, 0, uncompact(compacted, len(compacted), uncompacted, size, 7))
+		require.ElementsMatch(t, set, uncompacted)
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 1, 4, 0)
+
+		children := make([]H3Index, 0)
+		h3ToChildren(pentagon, 3, &children)
+		require.Len(t, children, maxH3ToChildrenSize(pentagon, 3))
+
+		set := make([]H3Index, 0, len(children))
+		for _, h := range children {
+			if h != H3_INVALID_INDEX {
+				set = append(set, h)
+			}
+		}
+
+		compacted := make([]H3Index, len(set))
+		require.Equal(t, 0, compact(set, compacted, len(set)))
+		require.Equal(t, pentagon, compacted[0])
+		for _, h := range compacted[1:] {
+			require.Equal(t, H3_INVALID_INDEX, h)
+		}
+	})
+}
+
+func TestCompactCellsInPlace(t *testing.T) {
+	sorted := func(cells []H3Index) []H3Index {
+		cells = append([]H3Index{}, cells...)
+		sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+		return cells
+	}
+
+	t.Run("sameAsCompact", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 3, 4, 0)
+		r := rand.New(rand.NewSource(47))
+		for _, origin := range []H3Index{0x85283473fffffff, pentagon} {
+			disk, err := GridDisk(origin, 2)
+			require.NoError(t, err)
+			cells, err := UncompactCells(disk, H3_GET_RESOLUTION(origin)+3)
+			require.NoError(t, err)
+			// drop a few cells, and shuffle the rest
+			cells = cells[:len(cells)-r.Intn(len(cells)/2)]
+			r.Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+
+			expected := make([]H3Index, len(cells))
+			require.Equal(t, 0, compact(cells, expected, len(cells)))
+			expected = subtractCells(expected, []H3Index{H3_INVALID_INDEX})
+
+			in := append([]H3Index{}, cells...)
+			compacted, err := CompactCellsInPlace(in)
+			require.NoError(t, err)
+			require.Equal(t, &in[0], &compacted[0], "compacted in place")
+			require.Equal(t, sorted(expected), sorted(compacted))
+			require.True(t, len(compacted) < len(cells))
+
+			uncompacted, err := UncompactCells(compacted, H3_GET_RESOLUTION(origin)+3)
+			require.NoError(t, err)
+			require.Equal(t, sorted(cells), sorted(uncompacted))
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		compacted, err := CompactCellsInPlace(nil)
+		require.NoError(t, err)
+		require.Empty(t, compacted)
+		_, err = CompactCellsInPlace([]H3Index{0x8928308280fffff, H3_INVALID_INDEX})
+		require.Equal(t, ErrInvalidIndex, err)
+		_, err = CompactCellsInPlace([]H3Index{0x8928308280fffff, 0x85283473fffffff})
+		require.Equal(t, ErrResolutionMismatch, err)
+		_, err = CompactCellsInPlace([]H3Index{0x8928308280fffff, 0x8928308280fffff})
+		require.Equal(t, ErrDuplicateInput, err)
+	})
+}
+
+func TestAppendUncompactedCells(t *testing.T) {
+	var pentagon H3Index
+	setH3Index(&pentagon, 2, 4, 0)
+	compacted := []H3Index{0x85283473fffffff, pentagon, 0x872830828ffffff}
+
+	size := maxUncompactSize(compacted, len(compacted), 7)
+	expected := make([]H3Index, size)
+	require.Equal(t, 0, uncompact(compacted, len(compacted), expected, size, 7))
+	expected = subtractCells(expected, []H3Index{H3_INVALID_INDEX})
+
+	dst := []H3Index{0x8928308280fffff}
+	cells, err := AppendUncompactedCells(dst, compacted, 7)
+	require.NoError(t, err)
+	require.Equal(t, append(dst, expected...), cells)
+
+	_, err = AppendUncompactedCells(nil, compacted, 6)
+	require.Equal(t, ErrResolutionMismatch, err)
+	_, err = AppendUncompactedCells(nil, compacted, 16)
+	require.Equal(t, ErrDomain, err)
+	_, err = AppendUncompactedCells(nil, []H3Index{H3_INVALID_INDEX}, 7)
+	require.Equal(t, ErrInvalidIndex, err)
+}
+
+func TestUncompactCellsParallel(t *testing.T) {
+	var pentagon H3Index
+	setH3Index(&pentagon, 2, 4, 0)
+	disk, err := GridDisk(0x85283473fffffff, 1)
+	require.NoError(t, err)
+	compacted := append(disk, pentagon, 0x8928308280fffff, 0x872830828ffffff)
+	for _, h := range []H3Index{0x85283473fffffff, pentagon} {
+		children, err := UncompactCells([]H3Index{h}, H3_GET_RESOLUTION(h)+1)
+		require.NoError(t, err)
+		compacted = append(compacted, children...)
+	}
+
+	expected, err := UncompactCells(compacted, 9)
+	require.NoError(t, err)
+	for _, workers := range []int{0, 1, 3, 64} {
+		cells, err := UncompactCellsParallel(compacted, 9, workers)
+		require.NoError(t, err)
+		require.Equal(t, expected, cells, "%d workers", workers)
+	}
+
+	cells, err := UncompactCellsParallel(nil, 9, 0)
+	require.NoError(t, err)
+	require.Empty(t, cells)
+	_, err = UncompactCellsParallel(compacted, 9, -1)
+	require.Equal(t, ErrDomain, err)
+	_, err = UncompactCellsParallel(compacted, 8, 0)
+	require.Equal(t, ErrResolutionMismatch, err)
+	res0 := make([]H3Index, res0IndexCount())
+	getRes0Indexes(res0)
+	_, err = UncompactCellsParallel(res0, 15, 0)
+	require.Equal(t, ErrDomain, err, "more cells than a slice holds")
+
+	if !testing.Short() {
+		// more than 2^24 cells, as many as the cover of a country at res 9
+		large, err := GridDisk(h3ToParent(0x85283473fffffff, 1), 1)
+		require.NoError(t, err)
+		large = large[:3]
+		expected, err := UncompactCells(large, 9)
+		require.NoError(t, err)
+		require.True(t, len(expected) > 1<<24)
+		cells, err := UncompactCellsParallel(large, 9, 0)
+		require.NoError(t, err)
+		require.Equal(t, expected, cells)
+	}
+	_, err = UncompactCellsParallel([]H3Index{H3_INVALID_INDEX}, 9, 0)
+	require.Equal(t, ErrInvalidIndex, err)
+}
+
+func TestGeoToCell(t *testing.T) {
+	h := H3Index(0x8928308280fffff)
+
+	center, err := CellToGeo(h)
+	require.NoError(t, err)
+
+	got, err := GeoToCell(center, 9)
+	require.NoError(t, err)
+	require.Equal(t, h, got)
+
+	boundary, err := CellToBoundary(h)
+	require.NoError(t, err)
+	require.Len(t, boundary.Verts, 6)
+
+	_, err = GeoToCell(center, 16)
+	require.Equal(t, ErrDomain, err)
+	_, err = GeoToCell(GeoCoord{Lat: math.NaN()}, 9)
+	require.Equal(t, ErrDomain, err)
+	_, err = CellToGeo(H3_INVALID_INDEX)
+	require.Equal(t, ErrInvalidIndex, err)
+	_, err = CellToBoundary(H3_INVALID_INDEX)
+	require.Equal(t, ErrInvalidIndex, err)
+}
+
+func TestCompactCells(t *testing.T) {
+	var pentagon H3Index
+	setH3Index(&pentagon, 5, 4, 0)
+
+	cells, err := UncompactCells([]H3Index{0x85283473fffffff, pentagon}, 7)
+	require.NoError(t, err)
+	require.Len(t, cells, 49+41)
+
+	compacted, err := CompactCells(cells)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []H3Index{0x85283473fffffff, pentagon}, compacted)
+
+	_, err = CompactCells(append(cells, cells[0]))
+	require.Equal(t, ErrDuplicateInput, err)
+	_, err = CompactCells([]H3Index{0x85283473fffffff, 0x8928308280fffff})
+	require.Equal(t, ErrResolutionMismatch, err)
+	_, err = UncompactCells([]H3Index{0x8928308280fffff}, 7)
+	require.Equal(t, ErrResolutionMismatch, err)
+	_, err = UncompactCells([]H3Index{0x8928308280fffff}, 16)
+	require.Equal(t, ErrDomain, err)
+	res0 := make([]H3Index, res0IndexCount())
+	getRes0Indexes(res0)
+	_, err = UncompactCells(res0, 15)
+	require.Equal(t, ErrDomain, err, "more cells than a slice holds")
+}
+
+func BenchmarkCompactCells(b *testing.B) {
+	disk, err := GridDisk(0x85283473fffffff, 3)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cells, err := UncompactCells(disk, 10)
+	if err != nil {
+		b.Fatal(err)
+	}
+	rand.New(rand.NewSource(47)).Shuffle(len(cells), func(i, j int) { cells[i], cells[j] = cells[j], cells[i] })
+	buf := make([]H3Index, len(cells))
+
+	b.Run("compact", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			compact(cells, buf, len(cells))
+		}
+	})
+	b.Run("inPlace", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			copy(buf, cells)
+			if _, err := CompactCellsInPlace(buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkUncompactCells(b *testing.B) {
+	var pentagon H3Index
+	setH3Index(&pentagon, 4, 4, 0)
+	compacted := []H3Index{0x85283473fffffff, pentagon}
+	size := maxUncompactSize(compacted, len(compacted), 10)
+	buf := make([]H3Index, size)
+
+	b.Run("uncompact", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			uncompact(compacted, len(compacted), buf, size, 10)
+		}
+	})
+	b.Run("append", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := AppendUncompactedCells(buf[:0], compacted, 10); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkUncompactCellsParallel(b *testing.B) {
+	// the compacted cover of a disk, with cells of several resolutions
+	disk, err := GridDisk(0x85283473fffffff, 3)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cells, err := UncompactCells(disk, 7)
+	if err != nil {
+		b.Fatal(err)
+	}
+	compacted, err := CompactCells(cells[len(cells)/3:])
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("serial", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := UncompactCells(compacted, 10); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := UncompactCellsParallel(compacted, 10, 0); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -151,12 +151,13 @@ func h3UnidirectionalEdgeIsValid(edge H3Index) bool {
 		return false
 	}
 
+	// The origin is validated first, h3IsPentagon assumes a valid base cell
 	origin := getOriginH3IndexFromUnidirectionalEdge(edge)
-	if h3IsPentagon(origin) && Direction(neighborDirection) == K_AXES_DIGIT {
+	if !h3IsValid(origin) {
 		return false
 	}
 
-	return h3IsValid(origin)
+	return !h3IsPentagon(origin) || Direction(neighborDirection) != K_AXES_DIGIT
 }
 
 /**
@@ -194,53 +195,119 @@ func getH3UnidirectionalEdgesFromHexagon(origin H3Index, edges []H3Index) {
 }
 
 /**
- * Whether the given coordinate has a matching vertex in the given geo boundary.
- * @param  vertex   Coordinate to check
- * @param  boundary Geo boundary to look in
- * @return          Whether a match was found
+ * Provides the coordinates defining the unidirectional edge.
+ * @param edge The unidirectional edge H3Index
+ * @param gb The geoboundary object to store the edge coordinates.
  */
-func _hasMatchingVertex(vertex *GeoCoord, boundary *GeoBoundary) bool {
-	for i := 0; i < boundary.numVerts; i++ {
-		if geoAlmostEqualThreshold(vertex, &boundary.Verts[i], 0.000001) {
-			return true
-		}
+func getH3UnidirectionalEdgeBoundary(edge H3Index, gb *GeoBoundary) {
+	// Get the origin and neighbor direction from the edge
+	direction := Direction(H3_GET_RESERVED_BITS(edge))
+	origin := getOriginH3IndexFromUnidirectionalEdge(edge)
+
+	// Get the start vertex for the edge
+	startVertex := vertexNumForDirection(origin, direction)
+	if startVertex == INVALID_VERTEX_NUM {
+		// This is not actually an edge (i.e. no valid direction),
+		// so return no vertices.
+		gb.numVerts = 0
+		return
 	}
-	return false
+
+	// Get the geo boundary for the appropriate vertexes of the origin. Note
+	// that while there are always 2 topological vertexes per edge, the
+	// resulting edge boundary may have an additional distortion vertex if it
+	// crosses an edge of the icosahedron.
+	var fijk FaceIJK
+	_h3ToFaceIjk(origin, &fijk)
+	_faceIjkToGeoBoundary(&fijk, H3_GET_RESOLUTION(origin), h3IsPentagon(origin), startVertex, 2, gb)
 }
 
 /**
- * Provides the coordinates defining the unidirectional edge.
- * @param edge The unidirectional edge H3Index
- * @param gb The geoboundary object to store the edge coordinates.
+ * AreNeighborCells returns whether the two cells share an edge.
+ * @param origin The origin cell.
+ * @param destination The destination cell.
+ * @return Whether the cells are neighbors.
  */
-func getH3UnidirectionalEdgeBoundary(edge H3Index, gb *GeoBoundary) {
-	// TODO: More efficient solution :)
-	origin := GeoBoundary{}
-	destination := GeoBoundary{}
-	postponedVertex := GeoCoord{}
-	hasPostponedVertex := false
-
-	h3ToGeoBoundary(getOriginH3IndexFromUnidirectionalEdge(edge), &origin)
-	h3ToGeoBoundary(getDestinationH3IndexFromUnidirectionalEdge(edge), &destination)
-
-	k := 0
-	for i := 0; i < origin.numVerts; i++ {
-		if _hasMatchingVertex(&origin.Verts[i], &destination) {
-			// If we are on vertex 0, we need to handle the case where it's the
-			// end of the edge, not the beginning.
-			if i == 0 && !_hasMatchingVertex(&origin.Verts[i+1], &destination) {
-				postponedVertex = origin.Verts[i]
-				hasPostponedVertex = true
-			} else {
-				gb.Verts[k] = origin.Verts[i]
-				k++
-			}
-		}
+func AreNeighborCells(origin H3Index, destination H3Index) (bool, error) {
+	if !h3IsValid(origin) || !h3IsValid(destination) {
+		return false, ErrInvalidIndex
+	}
+	if H3_GET_RESOLUTION(origin) != H3_GET_RESOLUTION(destination) {
+		return false, ErrResolutionMismatch
+	}
+	return h3IndexesAreNeighbors(origin, destination) == 1, nil
+}
+
+/**
+ * CellsToDirectedEdge returns the unidirectional edge from the origin cell to
+ * a neighboring destination cell.
+ * @param origin The origin cell.
+ * @param destination The destination cell.
+ * @return The unidirectional edge.
+ */
+func CellsToDirectedEdge(origin H3Index, destination H3Index) (H3Index, error) {
+	neighbors, err := AreNeighborCells(origin, destination)
+	if err != nil {
+		return H3_INVALID_INDEX, err
 	}
-	// If we postponed adding the last vertex, add it now
-	if hasPostponedVertex {
-		gb.Verts[k] = postponedVertex
-		k++
+	if !neighbors {
+		return H3_INVALID_INDEX, ErrNotNeighbors
 	}
-	gb.numVerts = k
+
+	edge := getH3UnidirectionalEdge(origin, destination)
+	if edge == H3_INVALID_INDEX {
+		return H3_INVALID_INDEX, ErrFailed
+	}
+	return edge, nil
+}
+
+/**
+ * DirectedEdgeToCells returns the origin and destination cells of a
+ * unidirectional edge.
+ * @param edge The unidirectional edge.
+ * @return The origin and destination cells.
+ */
+func DirectedEdgeToCells(edge H3Index) (H3Index, H3Index, error) {
+	if !h3UnidirectionalEdgeIsValid(edge) {
+		return H3_INVALID_INDEX, H3_INVALID_INDEX, ErrInvalidIndex
+	}
+
+	originDestination := make([]H3Index, 2)
+	getH3IndexesFromUnidirectionalEdge(edge, originDestination)
+	return originDestination[0], originDestination[1], nil
+}
+
+/**
+ * OriginToDirectedEdges returns the unidirectional edges leaving a cell, five
+ * for pentagons and six for hexagons.
+ * @param origin The origin cell.
+ * @return The unidirectional edges.
+ */
+func OriginToDirectedEdges(origin H3Index) ([]H3Index, error) {
+	if !h3IsValid(origin) {
+		return nil, ErrInvalidIndex
+	}
+
+	edges := make([]H3Index, 6)
+	getH3UnidirectionalEdgesFromHexagon(origin, edges)
+	if edges[0] == H3_INVALID_INDEX {
+		edges = edges[1:]
+	}
+	return edges, nil
+}
+
+/**
+ * DirectedEdgeToBoundary returns the coordinates of a unidirectional edge.
+ * @param edge The unidirectional edge.
+ * @return The vertices of the edge, in radians.
+ */
+func DirectedEdgeToBoundary(edge H3Index) (GeoBoundary, error) {
+	if !h3UnidirectionalEdgeIsValid(edge) {
+		return GeoBoundary{}, ErrInvalidIndex
+	}
+
+	gb := GeoBoundary{Verts: make([]GeoCoord, MAX_CELL_BNDRY_VERTS)}
+	getH3UnidirectionalEdgeBoundary(edge, &gb)
+	gb.Verts = gb.Verts[:gb.numVerts]
+	return gb, nil
 }
@@ -0,0 +1,77 @@
+package h3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCellsToDirectedEdge(t *testing.T) {
+	origin := H3Index(0x8928308280fffff)
+	ring, err := GridRing(origin, 1)
+	require.NoError(t, err)
+
+	edges, err := OriginToDirectedEdges(origin)
+	require.NoError(t, err)
+	require.Len(t, edges, 6)
+
+	for _, destination := range ring {
+		neighbors, err := AreNeighborCells(origin, destination)
+		require.NoError(t, err)
+		require.True(t, neighbors)
+
+		edge, err := CellsToDirectedEdge(origin, destination)
+		require.NoError(t, err)
+		require.Contains(t, edges, edge)
+
+		gotOrigin, gotDestination, err := DirectedEdgeToCells(edge)
+		require.NoError(t, err)
+		require.Equal(t, origin, gotOrigin)
+		require.Equal(t, destination, gotDestination)
+
+		boundary, err := DirectedEdgeToBoundary(edge)
+		require.NoError(t, err)
+		require.Len(t, boundary.Verts, 2)
+	}
+
+	t.Run("notNeighbors", func(t *testing.T) {
+		ring, err := GridRing(origin, 2)
+		require.NoError(t, err)
+		_, err = CellsToDirectedEdge(origin, ring[0])
+		require.Equal(t, ErrNotNeighbors, err)
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 5, 4, 0)
+		edges, err := OriginToDirectedEdges(pentagon)
+		require.NoError(t, err)
+		require.Len(t, edges, 5)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := DirectedEdgeToCells(origin)
+		require.Equal(t, ErrInvalidIndex, err)
+		_, err = DirectedEdgeToBoundary(origin)
+		require.Equal(t, ErrInvalidIndex, err)
+		_, err = AreNeighborCells(origin, 0x85283473fffffff)
+		require.Equal(t, ErrResolutionMismatch, err)
+	})
+}
+
+func Test_getH3UnidirectionalEdgeBoundary(t *testing.T) {
+	// the vertexes of an edge are found exactly, however close at fine resolutions
+	var center GeoCoord
+	h3ToGeo(0x8928308280fffff, &center)
+
+	for res := 0; res <= MAX_H3_RES; res++ {
+		origin := geoToH3(&center, res)
+		edges, err := OriginToDirectedEdges(origin)
+		require.NoError(t, err)
+		for _, edge := range edges {
+			boundary, err := DirectedEdgeToBoundary(edge)
+			require.NoError(t, err)
+			require.Len(t, boundary.Verts, 2, "res %d", res)
+		}
+	}
+}
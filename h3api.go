@@ -188,6 +188,6 @@ type LinkedGeoPolygon struct {
  * Each axis is spaced 120 degrees apart.
  */
 type CoordIJ struct {
-	i int ///< i component
-	j int ///< j component
+	I int ///< i component
+	J int ///< j component
 }
@@ -0,0 +1,197 @@
+package h3
+
+/**
+ * _commonResolution returns the finest resolution at which two valid cells
+ * have the same ancestor, or -1 when they are under different base cells.
+ * The ancestors agree on the base cell and on every digit up to that
+ * resolution.
+ */
+func _commonResolution(a H3Index, b H3Index) int {
+	if H3_GET_BASE_CELL(a) != H3_GET_BASE_CELL(b) {
+		return -1
+	}
+
+	res := H3_GET_RESOLUTION(a)
+	if resB := H3_GET_RESOLUTION(b); resB < res {
+		res = resB
+	}
+	for r := 1; r <= res; r++ {
+		if H3_GET_INDEX_DIGIT(a, r) != H3_GET_INDEX_DIGIT(b, r) {
+			return r - 1
+		}
+	}
+	return res
+}
+
+/**
+ * IsAncestorOf returns whether a is a strict ancestor of b, that is whether
+ * a is coarser than b and contains it. A cell is not its own ancestor.
+ *
+ * @param a The possible ancestor.
+ * @param b The possible descendant.
+ * @return Whether a is an ancestor of b, or ErrInvalidIndex.
+ */
+func IsAncestorOf(a H3Index, b H3Index) (bool, error) {
+	if !h3IsValid(a) || !h3IsValid(b) {
+		return false, ErrInvalidIndex
+	}
+	res := H3_GET_RESOLUTION(a)
+	return res < H3_GET_RESOLUTION(b) && _commonResolution(a, b) == res, nil
+}
+
+/**
+ * IsDescendantOf returns whether a is a strict descendant of b.
+ *
+ * @param a The possible descendant.
+ * @param b The possible ancestor.
+ * @return Whether a is a descendant of b, or ErrInvalidIndex.
+ */
+func IsDescendantOf(a H3Index, b H3Index) (bool, error) {
+	return IsAncestorOf(b, a)
+}
+
+/**
+ * CommonResolution returns the finest resolution at which two cells have the
+ * same ancestor. The cells may be of different resolutions.
+ *
+ * @param a The first cell.
+ * @param b The second cell.
+ * @return The resolution, or ErrNoCommonAncestor when the cells are under
+ *         different base cells.
+ */
+func CommonResolution(a H3Index, b H3Index) (int, error) {
+	return CellsCommonResolution([]H3Index{a, b})
+}
+
+/**
+ * LowestCommonAncestor returns the finest cell containing both cells, which
+ * is one of the cells itself when it contains the other.
+ *
+ * @param a The first cell.
+ * @param b The second cell.
+ * @return The common ancestor, or ErrNoCommonAncestor when the cells are
+ *         under different base cells.
+ */
+func LowestCommonAncestor(a H3Index, b H3Index) (H3Index, error) {
+	return CellsLowestCommonAncestor([]H3Index{a, b})
+}
+
+/**
+ * CellsCommonResolution returns the finest resolution at which all the cells
+ * have the same ancestor.
+ *
+ * @param cells Cells of any resolution.
+ * @return The resolution. ErrDomain is returned for an empty set and
+ *         ErrNoCommonAncestor when the cells are under different base cells.
+ */
+func CellsCommonResolution(cells []H3Index) (int, error) {
+	if len(cells) == 0 {
+		return -1, ErrDomain
+	}
+	for _, h := range cells {
+		if !h3IsValid(h) {
+			return -1, ErrInvalidIndex
+		}
+	}
+
+	res := H3_GET_RESOLUTION(cells[0])
+	for _, h := range cells[1:] {
+		if r := _commonResolution(cells[0], h); r < res {
+			res = r
+		}
+		if res < 0 {
+			return -1, ErrNoCommonAncestor
+		}
+	}
+	return res, nil
+}
+
+/**
+ * CellsAreDescendantsOf returns whether every cell is a strict descendant of
+ * h, that is whether h is an ancestor of the whole set.
+ *
+ * @param cells Cells of any resolution.
+ * @param h The possible ancestor.
+ * @return Whether all the cells are descendants of h. ErrDomain is returned
+ *         for an empty set and ErrInvalidIndex for invalid cells.
+ */
+func CellsAreDescendantsOf(cells []H3Index, h H3Index) (bool, error) {
+	if len(cells) == 0 {
+		return false, ErrDomain
+	}
+	descendants := true
+	for _, c := range cells {
+		descendant, err := IsDescendantOf(c, h)
+		if err != nil {
+			return false, err
+		}
+		descendants = descendants && descendant
+	}
+	return descendants, nil
+}
+
+/**
+ * CellsLowestCommonAncestor returns the finest cell containing all the cells.
+ *
+ * @param cells Cells of any resolution.
+ * @return The common ancestor, with the errors of CellsCommonResolution.
+ */
+func CellsLowestCommonAncestor(cells []H3Index) (H3Index, error) {
+	res, err := CellsCommonResolution(cells)
+	if err != nil {
+		return H3_INVALID_INDEX, err
+	}
+	return h3ToParent(cells[0], res), nil
+}
+
+/**
+ * LowestCommonAncestor returns the finest cell containing all the cells of
+ * the set, with the errors of CellsLowestCommonAncestor.
+ */
+func (s *CellSet) LowestCommonAncestor() (H3Index, error) {
+	return CellsLowestCommonAncestor(s.Cells())
+}
+
+/**
+ * CommonResolution returns the finest resolution at which all the cells of
+ * the set have the same ancestor, with the errors of CellsCommonResolution.
+ */
+func (s *CellSet) CommonResolution() (int, error) {
+	return CellsCommonResolution(s.Cells())
+}
+
+/**
+ * IsAncestorOf returns whether a cell of the set is a strict ancestor of h.
+ * As the set is normalized, this is whether h is covered by a coarser cell
+ * of the set.
+ *
+ * @param h The possible descendant.
+ * @return Whether the set holds an ancestor of h, or ErrInvalidIndex.
+ */
+func (s *CellSet) IsAncestorOf(h H3Index) (bool, error) {
+	if !h3IsValid(h) {
+		return false, ErrInvalidIndex
+	}
+	covering, ok := s._covering(h)
+	return ok && covering != h, nil
+}
+
+/**
+ * IsDescendantOf returns whether every cell of the set is a strict
+ * descendant of h, found from the number of cells stored under h without
+ * visiting them.
+ *
+ * @param h The possible ancestor.
+ * @return Whether all the cells are descendants of h. ErrDomain is returned
+ *         for an empty set and ErrInvalidIndex for an invalid h.
+ */
+func (s *CellSet) IsDescendantOf(h H3Index) (bool, error) {
+	if !h3IsValid(h) {
+		return false, ErrInvalidIndex
+	}
+	if s.Len() == 0 {
+		return false, ErrDomain
+	}
+	node := s.trie._node(h)
+	return node != nil && !node.stored && node.count == s.Len(), nil
+}
@@ -0,0 +1,161 @@
+package h3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAncestorOf(t *testing.T) {
+	cell := H3Index(0x8928308280fffff)
+	parent := h3ToParent(cell, 5)
+	var pentagon H3Index
+	setH3Index(&pentagon, 2, 4, 0)
+
+	for _, c := range []struct {
+		a, b     H3Index
+		ancestor bool
+	}{
+		{parent, cell, true},
+		{h3ToParent(cell, 0), cell, true},
+		{cell, cell, false},
+		{cell, parent, false},
+		{h3ToParent(cell, 8), h3ToCenterChild(cell, 15), true},
+		{_directChildren(parent)[1], cell, h3ToParent(cell, 6) == _directChildren(parent)[1]},
+		{0x85283473fffffff, cell, false},
+		{pentagon, h3ToCenterChild(pentagon, 9), true},
+		{pentagon, cell, false},
+	} {
+		ancestor, err := IsAncestorOf(c.a, c.b)
+		require.NoError(t, err)
+		require.Equal(t, c.ancestor, ancestor, "%x ancestor of %x", c.a, c.b)
+
+		descendant, err := IsDescendantOf(c.b, c.a)
+		require.NoError(t, err)
+		require.Equal(t, c.ancestor, descendant)
+	}
+
+	_, err := IsAncestorOf(H3_INVALID_INDEX, cell)
+	require.Equal(t, ErrInvalidIndex, err)
+	_, err = IsDescendantOf(cell, 0x8928308280ffff0)
+	require.Equal(t, ErrInvalidIndex, err)
+}
+
+func TestLowestCommonAncestor(t *testing.T) {
+	cell := H3Index(0x8928308280fffff)
+
+	t.Run("pairs", func(t *testing.T) {
+		disk, err := GridDisk(cell, 10)
+		require.NoError(t, err)
+		for _, h := range disk {
+			res, err := CommonResolution(cell, h)
+			require.NoError(t, err)
+			require.Equal(t, h3ToParent(cell, res), h3ToParent(h, res))
+			if res < 9 {
+				require.NotEqual(t, h3ToParent(cell, res+1), h3ToParent(h, res+1))
+			}
+
+			lca, err := LowestCommonAncestor(cell, h)
+			require.NoError(t, err)
+			require.Equal(t, h3ToParent(cell, res), lca)
+		}
+
+		lca, err := LowestCommonAncestor(h3ToParent(cell, 4), h3ToCenterChild(cell, 12))
+		require.NoError(t, err)
+		require.Equal(t, h3ToParent(cell, 4), lca, "mixed resolutions")
+
+		_, err = LowestCommonAncestor(cell, 0x8001fffffffffff)
+		require.Equal(t, ErrNoCommonAncestor, err)
+		_, err = CommonResolution(cell, H3_INVALID_INDEX)
+		require.Equal(t, ErrInvalidIndex, err)
+	})
+
+	t.Run("sets", func(t *testing.T) {
+		parent := h3ToParent(cell, 6)
+		children := _directChildren(h3ToCenterChild(parent, 7))
+		cells := []H3Index{children[0], children[5], h3ToCenterChild(_directChildren(parent)[2], 10)}
+
+		res, err := CellsCommonResolution(cells)
+		require.NoError(t, err)
+		require.Equal(t, 6, res)
+		lca, err := CellsLowestCommonAncestor(cells)
+		require.NoError(t, err)
+		require.Equal(t, parent, lca)
+
+		s, err := NewCellSet(cells...)
+		require.NoError(t, err)
+		lca, err = s.LowestCommonAncestor()
+		require.NoError(t, err)
+		require.Equal(t, parent, lca)
+
+		lca, err = CellsLowestCommonAncestor(cells[:1])
+		require.NoError(t, err)
+		require.Equal(t, cells[0], lca)
+
+		res, err = s.CommonResolution()
+		require.NoError(t, err)
+		require.Equal(t, 6, res)
+
+		_, err = CellsLowestCommonAncestor(nil)
+		require.Equal(t, ErrDomain, err)
+		_, err = CellsCommonResolution(append(cells, 0x8001fffffffffff))
+		require.Equal(t, ErrNoCommonAncestor, err)
+	})
+}
+
+func TestCellsAreDescendantsOf(t *testing.T) {
+	cell := H3Index(0x8928308280fffff)
+	parent := h3ToParent(cell, 6)
+	children := _directChildren(parent)
+	cells := []H3Index{children[1], h3ToCenterChild(children[4], 12)}
+	s, err := NewCellSet(cells...)
+	require.NoError(t, err)
+
+	for _, c := range []struct {
+		h           H3Index
+		descendants bool
+	}{
+		{parent, true},
+		{h3ToParent(cell, 2), true},
+		{children[1], false},
+		{children[4], false},
+		{cell, false},
+		{0x85283473fffffff, false},
+	} {
+		descendants, err := CellsAreDescendantsOf(cells, c.h)
+		require.NoError(t, err)
+		require.Equal(t, c.descendants, descendants, "under %x", c.h)
+
+		descendants, err = s.IsDescendantOf(c.h)
+		require.NoError(t, err)
+		require.Equal(t, c.descendants, descendants, "set under %x", c.h)
+	}
+
+	for _, c := range []struct {
+		h        H3Index
+		ancestor bool
+	}{
+		{h3ToCenterChild(children[1], 9), true},
+		{children[1], false},
+		{h3ToCenterChild(children[4], 15), true},
+		{h3ToCenterChild(children[4], 12), false},
+		{children[4], false},
+		{parent, false},
+		{children[2], false},
+	} {
+		ancestor, err := s.IsAncestorOf(c.h)
+		require.NoError(t, err)
+		require.Equal(t, c.ancestor, ancestor, "set ancestor of %x", c.h)
+	}
+
+	_, err = CellsAreDescendantsOf(nil, parent)
+	require.Equal(t, ErrDomain, err)
+	_, err = CellsAreDescendantsOf(append(cells, H3_INVALID_INDEX), parent)
+	require.Equal(t, ErrInvalidIndex, err)
+	_, err = (&CellSet{}).IsDescendantOf(parent)
+	require.Equal(t, ErrDomain, err)
+	_, err = s.IsDescendantOf(H3_INVALID_INDEX)
+	require.Equal(t, ErrInvalidIndex, err)
+	_, err = s.IsAncestorOf(H3_INVALID_INDEX)
+	require.Equal(t, ErrInvalidIndex, err)
+}
@@ -0,0 +1,170 @@
+package h3
+
+import (
+	"bytes"
+	"fmt"
+)
+
+/** The bit offset of the high bit in an H3 index. */
+const H3_HIGH_BIT_OFFSET = H3_MAX_OFFSET
+
+/**
+ * @brief One resolution digit of an inspected H3Index
+ */
+type DigitInspection struct {
+	Resolution int       ///< resolution of the digit, 1 to 15
+	Digit      Direction ///< value of the digit
+	Used       bool      ///< whether the digit is within the resolution of the index
+	Valid      bool      ///< whether the value is allowed at this position
+}
+
+/**
+ * @brief Field by field decoding of an H3Index
+ */
+type Inspection struct {
+	Index           H3Index                     ///< the inspected index
+	HighBit         int                         ///< bit 63, 0 in valid indexes
+	Mode            H3Mode                      ///< index mode
+	Reserved        int                         ///< reserved bits, the direction of unidirectional edges
+	Resolution      int                         ///< resolution
+	BaseCell        int                         ///< base cell number
+	BaseCellValid   bool                        ///< whether the base cell number is in range
+	IsPentagon      bool                        ///< whether the base cell is a pentagon
+	IsPolarPentagon bool                        ///< whether the base cell is a polar pentagon
+	HomeFace        int                         ///< home icosahedron face of the base cell, or -1
+	LeadingNonZero  Direction                   ///< first non center digit, CENTER_DIGIT if none
+	Digits          [MAX_H3_RES]DigitInspection ///< digits for resolutions 1 to 15
+	Valid           bool                        ///< whether h3IsValid accepts the index
+	Reason          string                      ///< why h3IsValid rejects the index
+}
+
+/**
+ * String returns the name of the direction constant.
+ */
+func (d Direction) String() string {
+	switch d {
+	case CENTER_DIGIT:
+		return "CENTER_DIGIT"
+	case K_AXES_DIGIT:
+		return "K_AXES_DIGIT"
+	case J_AXES_DIGIT:
+		return "J_AXES_DIGIT"
+	case JK_AXES_DIGIT:
+		return "JK_AXES_DIGIT"
+	case I_AXES_DIGIT:
+		return "I_AXES_DIGIT"
+	case IK_AXES_DIGIT:
+		return "IK_AXES_DIGIT"
+	case IJ_AXES_DIGIT:
+		return "IJ_AXES_DIGIT"
+	case INVALID_DIGIT:
+		return "INVALID_DIGIT"
+	}
+	return fmt.Sprintf("Direction(%d)", uint(d))
+}
+
+/**
+ * String returns the name of the mode constant.
+ */
+func (m H3Mode) String() string {
+	switch m {
+	case H3_HEXAGON_MODE:
+		return "H3_HEXAGON_MODE"
+	case H3_UNIEDGE_MODE:
+		return "H3_UNIEDGE_MODE"
+	}
+	return fmt.Sprintf("H3Mode(%d)", int(m))
+}
+
+/**
+ * Inspect decodes every field of an index, valid or not.
+ *
+ * @param h The H3 index to inspect.
+ * @return The decoded fields and, for invalid indexes, the reason h3IsValid
+ *         rejects it.
+ */
+func Inspect(h H3Index) Inspection {
+	in := Inspection{
+		Index:          h,
+		HighBit:        int(uint64(h) >> H3_HIGH_BIT_OFFSET),
+		Mode:           H3_GET_MODE(h),
+		Reserved:       H3_GET_RESERVED_BITS(h),
+		Resolution:     H3_GET_RESOLUTION(h),
+		BaseCell:       H3_GET_BASE_CELL(h),
+		HomeFace:       -1,
+		LeadingNonZero: CENTER_DIGIT,
+	}
+	if err := Validate(h); err != nil {
+		in.Reason = err.(*IndexError).reason()
+	} else {
+		in.Valid = true
+	}
+
+	if in.BaseCell < NUM_BASE_CELLS {
+		in.BaseCellValid = true
+		in.IsPentagon = _isBaseCellPentagon(in.BaseCell)
+		in.IsPolarPentagon = _isBaseCellPolarPentagon(in.BaseCell)
+		in.HomeFace = baseCellData[in.BaseCell].homeFijk.face
+	}
+
+	for r := 1; r <= MAX_H3_RES; r++ {
+		d := DigitInspection{
+			Resolution: r,
+			Digit:      H3_GET_INDEX_DIGIT(h, r),
+			Used:       r <= in.Resolution,
+		}
+		if d.Used {
+			d.Valid = d.Digit < NUM_DIGITS
+			if in.LeadingNonZero == CENTER_DIGIT && d.Digit != CENTER_DIGIT {
+				in.LeadingNonZero = d.Digit
+				if in.IsPentagon && d.Digit == K_AXES_DIGIT {
+					d.Valid = false
+				}
+			}
+		} else {
+			d.Valid = d.Digit == INVALID_DIGIT
+		}
+		in.Digits[r-1] = d
+	}
+
+	return in
+}
+
+/**
+ * String formats the inspection as a multi line report.
+ */
+func (in Inspection) String() string {
+	buf := bytes.NewBuffer(nil)
+
+	fmt.Fprintf(buf, "index       %s\n", h3ToString(in.Index))
+	if in.Valid {
+		fmt.Fprintf(buf, "valid       true\n")
+	} else {
+		fmt.Fprintf(buf, "valid       false: %s\n", in.Reason)
+	}
+	fmt.Fprintf(buf, "high bit    %d\n", in.HighBit)
+	fmt.Fprintf(buf, "mode        %d (%s)\n", int(in.Mode), in.Mode)
+	fmt.Fprintf(buf, "reserved    %d\n", in.Reserved)
+	fmt.Fprintf(buf, "resolution  %d\n", in.Resolution)
+	if in.BaseCellValid {
+		fmt.Fprintf(buf, "base cell   %d (pentagon: %t, polar pentagon: %t, home face: %d)\n",
+			in.BaseCell, in.IsPentagon, in.IsPolarPentagon, in.HomeFace)
+	} else {
+		fmt.Fprintf(buf, "base cell   %d (out of range)\n", in.BaseCell)
+	}
+
+	for _, d := range in.Digits {
+		note := ""
+		switch {
+		case !d.Used && d.Valid:
+			note = " (unused)"
+		case !d.Used:
+			note = " (unused, should be 7)"
+		case !d.Valid:
+			note = " (invalid)"
+		}
+		fmt.Fprintf(buf, "digit %-5d %d %s%s\n", d.Resolution, uint(d.Digit), d.Digit, note)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,84 @@
+package h3
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		in := Inspect(0x8928308280fffff)
+		require.True(t, in.Valid)
+		require.Empty(t, in.Reason)
+		require.Equal(t, H3_HEXAGON_MODE, in.Mode)
+		require.Equal(t, 9, in.Resolution)
+		require.Equal(t, 20, in.BaseCell)
+		require.False(t, in.IsPentagon)
+		require.Equal(t, Direction(0), in.Digits[0].Digit)
+		require.True(t, in.Digits[8].Used)
+		require.False(t, in.Digits[9].Used)
+		require.True(t, in.Digits[9].Valid)
+		require.Equal(t, INVALID_DIGIT, in.Digits[9].Digit)
+		require.Contains(t, in.String(), "base cell   20 (pentagon: false")
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		var h H3Index
+		setH3Index(&h, 2, 117, 0)
+		H3_SET_INDEX_DIGIT(&h, 2, K_AXES_DIGIT)
+
+		in := Inspect(h)
+		require.False(t, in.Valid)
+		require.True(t, in.IsPentagon)
+		require.True(t, in.IsPolarPentagon)
+		require.Equal(t, K_AXES_DIGIT, in.LeadingNonZero)
+		require.False(t, in.Digits[1].Valid)
+		require.Contains(t, in.Reason, "res 2")
+		require.Contains(t, in.String(), "K_AXES_DIGIT (invalid)")
+	})
+
+	t.Run("trailing", func(t *testing.T) {
+		h := H3Index(0x8928308280fffff)
+		H3_SET_INDEX_DIGIT(&h, 12, J_AXES_DIGIT)
+
+		in := Inspect(h)
+		require.False(t, in.Valid)
+		require.Equal(t, "unused digit 2 at res 12 is not 7", in.Reason)
+		require.False(t, in.Digits[11].Valid)
+	})
+
+	t.Run("baseCell", func(t *testing.T) {
+		h := H3Index(0x8928308280fffff)
+		H3_SET_BASE_CELL(&h, 122)
+
+		in := Inspect(h)
+		require.False(t, in.BaseCellValid)
+		require.Equal(t, -1, in.HomeFace)
+		require.Equal(t, "base cell 122 is out of range", in.Reason)
+	})
+
+	t.Run("mode", func(t *testing.T) {
+		edges := make([]H3Index, 6)
+		getH3UnidirectionalEdgesFromHexagon(0x8928308280fffff, edges)
+
+		in := Inspect(edges[1])
+		require.Equal(t, H3_UNIEDGE_MODE, in.Mode)
+		require.Equal(t, 2, in.Reserved)
+		require.True(t, strings.HasPrefix(in.Reason, "mode 2 is not"))
+	})
+
+	t.Run("agreesWithH3IsValid", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 10000; i++ {
+			h := H3Index(0x8928308280fffff)
+			// flip a few bits of a valid index
+			for j := 0; j < 1+r.Intn(3); j++ {
+				h ^= 1 << uint(r.Intn(64))
+			}
+			require.Equal(t, h3IsValid(h), Inspect(h).Valid, "%x", h)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package h3
+
+import "encoding/binary"
+
+/** Length of the byte key of an H3Index. */
+const H3_KEY_LENGTH = 8
+
+/**
+ * ChildRange returns the smallest and the largest index of the descendants
+ * of a cell at a given resolution.
+ *
+ * The descendants only differ in the digits below the resolution of the
+ * cell, so every valid cell of resolution childRes within [min, max] is a
+ * descendant, which lets ordered stores list the descendants with a single
+ * range scan. Indexes in the range that are not valid cells, such as those
+ * with a digit of 7 or in the deleted subsequence of a pentagon, never
+ * occur as keys.
+ *
+ * @param h The parent cell.
+ * @param childRes The resolution of the descendants, not coarser than h.
+ * @return The first and the last descendant in index order. ErrDomain is
+ *         returned for an invalid childRes.
+ */
+func ChildRange(h H3Index, childRes int) (min H3Index, max H3Index, err error) {
+	if !h3IsValid(h) {
+		return H3_INVALID_INDEX, H3_INVALID_INDEX, ErrInvalidIndex
+	}
+	if !_isValidChildRes(H3_GET_RESOLUTION(h), childRes) {
+		return H3_INVALID_INDEX, H3_INVALID_INDEX, ErrDomain
+	}
+
+	min = h
+	H3_SET_RESOLUTION(&min, childRes)
+	max = min
+	for r := H3_GET_RESOLUTION(h) + 1; r <= childRes; r++ {
+		H3_SET_INDEX_DIGIT(&min, r, CENTER_DIGIT)
+		H3_SET_INDEX_DIGIT(&max, r, IJ_AXES_DIGIT)
+	}
+	return min, max, nil
+}
+
+/**
+ * ChildKeyRange returns the byte keys bounding the descendants of a cell at
+ * a given resolution, as the half open range [start, limit) most key value
+ * stores scan.
+ *
+ * @param h The parent cell.
+ * @param childRes The resolution of the descendants, not coarser than h.
+ * @return The key of the first descendant and the key following the last.
+ */
+func ChildKeyRange(h H3Index, childRes int) (start []byte, limit []byte, err error) {
+	min, max, err := ChildRange(h, childRes)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the mode bits are never all set, so the increment can not overflow
+	return min.Key(), (max + 1).Key(), nil
+}
+
+/**
+ * Key returns the big-endian byte encoding of the index. Keys compare
+ * bytewise in the same order as the indexes compare as integers.
+ */
+func (h H3Index) Key() []byte {
+	return h.AppendKey(make([]byte, 0, H3_KEY_LENGTH))
+}
+
+/**
+ * AppendKey appends the key of the index to dst.
+ */
+func (h H3Index) AppendKey(dst []byte) []byte {
+	var key [H3_KEY_LENGTH]byte
+	binary.BigEndian.PutUint64(key[:], uint64(h))
+	return append(dst, key[:]...)
+}
+
+/**
+ * H3IndexFromKey decodes a key produced by Key.
+ *
+ * @param key The key.
+ * @return The index, or ErrDomain if the key is not H3_KEY_LENGTH bytes long.
+ *         The index is not validated.
+ */
+func H3IndexFromKey(key []byte) (H3Index, error) {
+	if len(key) != H3_KEY_LENGTH {
+		return H3_INVALID_INDEX, ErrDomain
+	}
+	return H3Index(binary.BigEndian.Uint64(key)), nil
+}
@@ -0,0 +1,107 @@
+package h3
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChildRange(t *testing.T) {
+	t.Run("hexagon", func(t *testing.T) {
+		parent := H3Index(0x85283473fffffff)
+		children, err := UncompactCells([]H3Index{parent}, 8)
+		require.NoError(t, err)
+
+		min, max, err := ChildRange(parent, 8)
+		require.NoError(t, err)
+		sort.Slice(children, func(i, j int) bool { return children[i] < children[j] })
+		require.Equal(t, children[0], min)
+		require.Equal(t, children[len(children)-1], max)
+
+		// every res 8 cell in the range is a descendant
+		disk, err := GridDisk(h3ToCenterChild(parent, 8), 30)
+		require.NoError(t, err)
+		inside := 0
+		for _, h := range disk {
+			in := h >= min && h <= max
+			require.Equal(t, h3ToParent(h, 5) == parent, in, "cell %x", h)
+			if in {
+				inside++
+			}
+		}
+		require.True(t, inside > 0 && inside < len(disk))
+
+		min, max, err = ChildRange(parent, 5)
+		require.NoError(t, err)
+		require.Equal(t, parent, min)
+		require.Equal(t, parent, max)
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 2, 4, 0)
+		children, err := UncompactCells([]H3Index{pentagon}, 4)
+		require.NoError(t, err)
+
+		min, max, err := ChildRange(pentagon, 4)
+		require.NoError(t, err)
+		for _, h := range children {
+			require.True(t, h >= min && h <= max)
+		}
+		require.Contains(t, children, min)
+		require.Contains(t, children, max)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := ChildRange(H3_INVALID_INDEX, 5)
+		require.Equal(t, ErrInvalidIndex, err)
+		_, _, err = ChildRange(0x85283473fffffff, 4)
+		require.Equal(t, ErrDomain, err)
+		_, _, err = ChildRange(0x85283473fffffff, 16)
+		require.Equal(t, ErrDomain, err)
+		_, _, err = ChildKeyRange(0x85283473fffffff, 16)
+		require.Equal(t, ErrDomain, err)
+	})
+}
+
+func TestH3IndexKey(t *testing.T) {
+	disk, err := GridDisk(0x85283473fffffff, 3)
+	require.NoError(t, err)
+	cells := append(disk, 0x8928308280fffff, 0x8001fffffffffff)
+
+	keys := make([][]byte, len(cells))
+	for i, h := range cells {
+		keys[i] = h.Key()
+		require.Len(t, keys[i], H3_KEY_LENGTH)
+
+		decoded, err := H3IndexFromKey(keys[i])
+		require.NoError(t, err)
+		require.Equal(t, h, decoded)
+	}
+
+	sort.Slice(cells, func(i, j int) bool { return cells[i] < cells[j] })
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+	for i := range cells {
+		require.Equal(t, cells[i].Key(), keys[i], "same order")
+	}
+
+	require.Equal(t, []byte{1, 0x08, 0x92, 0x83, 0x08, 0x28, 0x0f, 0xff, 0xff},
+		H3Index(0x8928308280fffff).AppendKey([]byte{1}))
+
+	_, err = H3IndexFromKey([]byte{1, 2, 3})
+	require.Equal(t, ErrDomain, err)
+
+	t.Run("childKeyRange", func(t *testing.T) {
+		parent := H3Index(0x85283473fffffff)
+		start, limit, err := ChildKeyRange(parent, 9)
+		require.NoError(t, err)
+
+		for _, h := range []H3Index{h3ToCenterChild(parent, 9), 0x8928308280fffff} {
+			key := h.Key()
+			in := bytes.Compare(key, start) >= 0 && bytes.Compare(key, limit) < 0
+			require.Equal(t, h3ToParent(h, 5) == parent, in, "cell %x", h)
+		}
+	})
+}
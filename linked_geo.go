@@ -327,3 +327,35 @@ func normalizeMultiPolygon(root *LinkedGeoPolygon) int {
 
 	return resultCode
 }
+
+/**
+ * Convert a linked geo structure to a GeoMultiPolygon. The first loop of
+ * each linked polygon becomes the geofence, the others become its holes.
+ * Polygons without loops are skipped.
+ * @param  root Root polygon of the linked geo structure
+ * @return      The multi polygon
+ */
+func linkedGeoToMultiPolygon(root *LinkedGeoPolygon) GeoMultiPolygon {
+	var polygons []GeoPolygon
+
+	var toGeofence = func(loop *LinkedGeoLoop) Geofence {
+		verts := make([]GeoCoord, 0, countLinkedCoords(loop))
+		for coord := loop.first; coord != nil; coord = coord.next {
+			verts = append(verts, coord.vertex)
+		}
+		return NewGeofence(verts)
+	}
+
+	for polygon := root; polygon != nil; polygon = polygon.next {
+		if polygon.first == nil {
+			continue
+		}
+		var holes []Geofence
+		for loop := polygon.first.next; loop != nil; loop = loop.next {
+			holes = append(holes, toGeofence(loop))
+		}
+		polygons = append(polygons, NewGeoPolygon(toGeofence(polygon.first), holes...))
+	}
+
+	return NewGeoMultiPolygon(polygons...)
+}
@@ -292,7 +292,7 @@ func localIjkToH3(origin H3Index, ijk *CoordIJK, out *H3Index) int {
 	// adjust r for the fact that the res 0 base cell offsets the indexing
 	// digits
 	for r := res - 1; r >= 0; r-- {
-		lastIJK := &CoordIJK{}
+		lastIJK := ijkCopy
 		var lastCenter CoordIJK
 		if isResClassIII(r + 1) {
 			// rotate ccw
@@ -307,7 +307,7 @@ func localIjkToH3(origin H3Index, ijk *CoordIJK, out *H3Index) int {
 		}
 
 		var diff CoordIJK
-		_ijkSub(lastIJK, &lastCenter, &diff)
+		_ijkSub(&lastIJK, &lastCenter, &diff)
 		_ijkNormalize(&diff)
 		H3_SET_INDEX_DIGIT(out, r+1, _unitIjkToDigit(&diff))
 	}
@@ -486,6 +486,67 @@ func experimentalLocalIjToH3(origin H3Index, ij *CoordIJ, out *H3Index) int {
 	return localIjkToH3(origin, &ijk, out)
 }
 
+/**
+ * CellToLocalIJ produces local ij coordinates for a cell anchored by an
+ * origin cell of the same resolution.
+ *
+ * The coordinate space may have deleted regions or warping due to pentagonal
+ * distortion, and coordinates are only comparable if they come from the same
+ * origin. The origin itself is not necessarily at (0, 0).
+ *
+ * @param origin An anchoring cell for the ij coordinate system.
+ * @param h Cell to find the coordinates of.
+ * @return The ij coordinates of h. ErrFailed is returned when the cells are
+ *         too far apart and ErrPentagon when h is on the other side of a
+ *         pentagon.
+ */
+func CellToLocalIJ(origin H3Index, h H3Index) (CoordIJ, error) {
+	if !h3IsValid(origin) || !h3IsValid(h) {
+		return CoordIJ{}, ErrInvalidIndex
+	}
+
+	var ij CoordIJ
+	switch experimentalH3ToLocalIj(origin, h, &ij) {
+	case 0:
+		return ij, nil
+	case 1:
+		return CoordIJ{}, ErrResolutionMismatch
+	case 2:
+		return CoordIJ{}, ErrFailed
+	default:
+		return CoordIJ{}, ErrPentagon
+	}
+}
+
+/**
+ * LocalIJToCell produces the cell at the given local ij coordinates anchored
+ * by an origin cell. It is the inverse of CellToLocalIJ.
+ *
+ * @param origin An anchoring cell for the ij coordinate system.
+ * @param ij Coordinates to find the cell of.
+ * @return The cell at the coordinates. ErrFailed is returned when the
+ *         coordinates are too far from the origin and ErrPentagon when they
+ *         fall in a deleted pentagon subsequence.
+ */
+func LocalIJToCell(origin H3Index, ij CoordIJ) (H3Index, error) {
+	if !h3IsValid(origin) {
+		return H3_INVALID_INDEX, ErrInvalidIndex
+	}
+
+	var out H3Index
+	switch experimentalLocalIjToH3(origin, &ij, &out) {
+	case 0:
+		if !h3IsValid(out) {
+			return H3_INVALID_INDEX, ErrPentagon
+		}
+		return out, nil
+	case 1, 2:
+		return H3_INVALID_INDEX, ErrFailed
+	default:
+		return H3_INVALID_INDEX, ErrPentagon
+	}
+}
+
 /**
 * Produces the grid distance between the two indexes.
 *
@@ -632,3 +693,206 @@ func h3Line(start H3Index, end H3Index, out []H3Index) int {
 
 	return 0
 }
+
+/**
+ * Estimated grid distance, used to decide whether an exact search is cheap.
+ * Adjacent cell centers are about sqrt(3) edge lengths apart.
+ *
+ * @param start Start index
+ * @param end End index
+ * @return The estimated number of steps between the two cells
+ */
+func _gridDistanceEstimate(start H3Index, end H3Index) int {
+	spacing := math.Sqrt(3) * edgeLengthKm(H3_GET_RESOLUTION(start))
+	var a, b GeoCoord
+	h3ToGeo(start, &a)
+	h3ToGeo(end, &b)
+	return int(math.Ceil(_geoDistKm(&a, &b) / spacing))
+}
+
+/**
+ * Finds a shortest path between two indexes with a breadth first search.
+ *
+ * @param start Start index of the path
+ * @param end End index of the path
+ * @param maxCells Maximum number of indexes to visit before giving up
+ * @return The path from start to end inclusive, or nil if the search visited
+ *         more than maxCells indexes.
+ */
+func _gridPathBfs(start H3Index, end H3Index, maxCells int) []H3Index {
+	parents := map[H3Index]H3Index{start: H3_INVALID_INDEX}
+	queue := []H3Index{start}
+
+	for head := 0; head < len(queue); head++ {
+		current := queue[head]
+		if current == end {
+			var path []H3Index
+			for h := end; h != H3_INVALID_INDEX; h = parents[h] {
+				path = append(path, h)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path
+		}
+
+		for dir := 0; dir < 6; dir++ {
+			rotations := 0
+			neighbor := h3NeighborRotations(current, DIRECTIONS[dir], &rotations)
+			if neighbor == H3_INVALID_INDEX {
+				continue
+			}
+			if _, ok := parents[neighbor]; ok {
+				continue
+			}
+			parents[neighbor] = current
+			queue = append(queue, neighbor)
+		}
+
+		if len(queue) > maxCells {
+			return nil
+		}
+	}
+
+	return nil // LCOV_EXCL_LINE
+}
+
+/**
+ * Finds a path between two indexes by recursively routing through the cell
+ * containing the great circle midpoint of their centers, until each leg can
+ * be drawn with h3Line or is short enough for a breadth first search.
+ *
+ * @param start Start index of the path
+ * @param end End index of the path
+ * @param out Path to append to, not including start
+ * @return The extended path, or nil on failure.
+ */
+func _gridPathComposed(start H3Index, end H3Index, out []H3Index) []H3Index {
+	if size := h3LineSize(start, end); size > 0 {
+		line := make([]H3Index, size)
+		if h3Line(start, end, line) == 0 {
+			return append(out, line[1:]...)
+		}
+	}
+
+	var startCenter, endCenter GeoCoord
+	h3ToGeo(start, &startCenter)
+	h3ToGeo(end, &endCenter)
+	midpoint := Destination(startCenter, Bearing(startCenter, endCenter), Distance(startCenter, endCenter, UNIT_RADS)/2, UNIT_RADS)
+	mid := geoToH3(&midpoint, H3_GET_RESOLUTION(start))
+
+	if mid == start || mid == end || mid == H3_INVALID_INDEX {
+		// The cells are within a few steps of each other
+		path := _gridPathBfs(start, end, maxKringSize(8))
+		if path == nil {
+			return nil // LCOV_EXCL_LINE
+		}
+		return append(out, path[1:]...)
+	}
+
+	out = _gridPathComposed(start, mid, out)
+	if out == nil {
+		return nil // LCOV_EXCL_LINE
+	}
+	return _gridPathComposed(mid, end, out)
+}
+
+/**
+ * Removes any loops from a path, so that no index is visited twice. Every
+ * index in the result is still a neighbor of the preceding index.
+ *
+ * @param path The path to simplify, modified in place
+ * @return The simplified path
+ */
+func _gridPathRemoveLoops(path []H3Index) []H3Index {
+	positions := make(map[H3Index]int, len(path))
+	n := 0
+	for _, h := range path {
+		if pos, ok := positions[h]; ok {
+			for _, removed := range path[pos+1 : n] {
+				delete(positions, removed)
+			}
+			n = pos + 1
+			continue
+		}
+		positions[h] = n
+		path[n] = h
+		n++
+	}
+	return path[:n]
+}
+
+/**
+ * GridPath returns a path of cells from start to end (inclusive), where
+ * every cell is a neighbor of the preceding cell.
+ *
+ * The line drawn in local ij coordinates is used when possible. When that
+ * fails, for example because the cells are on non-neighboring base cells or
+ * on opposite sides of a pentagon, nearby cells are connected with a breadth
+ * first search and distant cells are connected through the cells at the
+ * great circle midpoints between them.
+ *
+ * @param start Start cell of the path
+ * @param end End cell of the path
+ * @return The path, and whether it is known to be a shortest path.
+ */
+func GridPath(start H3Index, end H3Index) ([]H3Index, bool, error) {
+	if !h3IsValid(start) || !h3IsValid(end) {
+		return nil, false, ErrInvalidIndex
+	}
+	if H3_GET_RESOLUTION(start) != H3_GET_RESOLUTION(end) {
+		return nil, false, ErrResolutionMismatch
+	}
+
+	if size := h3LineSize(start, end); size > 0 {
+		line := make([]H3Index, size)
+		if h3Line(start, end, line) == 0 {
+			return line, true, nil
+		}
+	}
+
+	if estimate := _gridDistanceEstimate(start, end); estimate <= 64 {
+		// Any shortest path is at most about twice the estimate in the
+		// distorted areas, which bounds the search
+		if path := _gridPathBfs(start, end, maxKringSize(2*estimate+2)); path != nil {
+			return path, true, nil
+		}
+	}
+
+	path := _gridPathComposed(start, end, []H3Index{start})
+	if path == nil {
+		return nil, false, ErrFailed // LCOV_EXCL_LINE
+	}
+	return _gridPathRemoveLoops(path), false, nil
+}
+
+/**
+ * GridDistance returns the grid distance between two cells of the same
+ * resolution.
+ *
+ * When the distance can not be computed in local ij coordinates, it falls
+ * back on the length of the path found by GridPath. The result is then only
+ * an upper bound unless exact is true.
+ *
+ * @param origin Cell to find the distance from.
+ * @param h Cell to find the distance to.
+ * @return The distance, and whether it is known to be exact.
+ */
+func GridDistance(origin H3Index, h H3Index) (int, bool, error) {
+	if !h3IsValid(origin) || !h3IsValid(h) {
+		return 0, false, ErrInvalidIndex
+	}
+	if H3_GET_RESOLUTION(origin) != H3_GET_RESOLUTION(h) {
+		return 0, false, ErrResolutionMismatch
+	}
+
+	if distance := h3Distance(origin, h); distance >= 0 {
+		return distance, true, nil
+	}
+
+	path, exact, err := GridPath(origin, h)
+	if err != nil {
+		return 0, false, err // LCOV_EXCL_LINE
+	}
+	return len(path) - 1, exact, nil
+}
@@ -0,0 +1,197 @@
+package h3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireGridPath(t *testing.T, path []H3Index, start H3Index, end H3Index) {
+	require.Equal(t, start, path[0], "path starts at start")
+	require.Equal(t, end, path[len(path)-1], "path ends at end")
+
+	seen := map[H3Index]bool{}
+	for i := range path {
+		require.False(t, seen[path[i]], "path does not revisit %x", path[i])
+		seen[path[i]] = true
+		if i > 0 {
+			require.Equal(t, 1, h3IndexesAreNeighbors(path[i-1], path[i]), "%x neighbors %x", path[i-1], path[i])
+		}
+	}
+}
+
+func Test_localIjkToH3(t *testing.T) {
+	// the digit of each resolution is taken from the coordinates of the
+	// finer resolution, so every digit of a round trip must survive
+	var pentagonNeighbor H3Index
+	setH3Index(&pentagonNeighbor, 9, 4, IJ_AXES_DIGIT)
+	for _, origin := range []H3Index{0x8928308280fffff, 0x8f2830828052d25, pentagonNeighbor} {
+		disk, err := GridDisk(origin, 10)
+		require.NoError(t, err)
+
+		for _, h := range disk {
+			var ijk CoordIJK
+			if h3ToLocalIjk(origin, h, &ijk) != 0 {
+				continue // across the deleted subsequence of the pentagon
+			}
+
+			var out H3Index
+			require.Equal(t, 0, localIjkToH3(origin, &ijk, &out))
+			require.Equal(t, h, out, "round trip of %x from %x", h, origin)
+		}
+	}
+}
+
+func Test_h3Line(t *testing.T) {
+	start := H3Index(0x8928308280fffff)
+	disk, err := GridDisk(start, 6)
+	require.NoError(t, err)
+
+	for _, end := range disk {
+		line := make([]H3Index, h3LineSize(start, end))
+		require.Equal(t, 0, h3Line(start, end, line))
+		requireGridPath(t, line, start, end)
+	}
+}
+
+func TestGridPath(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		_, _, err := GridPath(H3_INVALID_INDEX, 0x8928308280fffff)
+		require.Equal(t, ErrInvalidIndex, err)
+
+		_, _, err = GridPath(0x8928308280fffff, 0x85283473fffffff)
+		require.Equal(t, ErrResolutionMismatch, err)
+	})
+
+	t.Run("localIj", func(t *testing.T) {
+		start := H3Index(0x8928308280fffff)
+		disk, err := GridDisk(start, 3)
+		require.NoError(t, err)
+		end := disk[len(disk)-1]
+
+		path, exact, err := GridPath(start, end)
+		require.NoError(t, err)
+		require.True(t, exact)
+		require.Len(t, path, 4)
+		requireGridPath(t, path, start, end)
+	})
+
+	t.Run("acrossPentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 5, 4, 0)
+
+		cells, distances, err := GridDiskDistances(pentagon, 3)
+		require.NoError(t, err)
+
+		for i, start := range cells {
+			for j, end := range cells {
+				if distances[i] != 3 || distances[j] != 3 {
+					continue
+				}
+				path, exact, err := GridPath(start, end)
+				require.NoError(t, err)
+				require.True(t, exact, "short paths are searched exhaustively")
+				require.True(t, len(path)-1 <= 6)
+				requireGridPath(t, path, start, end)
+			}
+		}
+	})
+
+	t.Run("distant", func(t *testing.T) {
+		coords := [][2]*GeoCoord{
+			{GeoFromWGS84(0, 0), GeoFromWGS84(40, 60)},
+			{GeoFromWGS84(-40, -60), GeoFromWGS84(40, 60)},
+			{GeoFromWGS84(60, 170), GeoFromWGS84(60, -170)},
+		}
+		for _, c := range coords {
+			for res := 0; res <= 6; res++ {
+				start := geoToH3(c[0], res)
+				end := geoToH3(c[1], res)
+
+				path, _, err := GridPath(start, end)
+				require.NoError(t, err)
+				requireGridPath(t, path, start, end)
+
+				distance, _, err := GridDistance(start, end)
+				require.NoError(t, err)
+				require.Equal(t, len(path)-1, distance)
+			}
+		}
+	})
+}
+
+func TestGridDistance(t *testing.T) {
+	origin := H3Index(0x8928308280fffff)
+	cells, distances, err := GridDiskDistances(origin, 4)
+	require.NoError(t, err)
+
+	for i, h := range cells {
+		distance, exact, err := GridDistance(origin, h)
+		require.NoError(t, err)
+		require.True(t, exact)
+		require.Equal(t, distances[i], distance)
+	}
+
+	_, _, err = GridDistance(origin, 0x85283473fffffff)
+	require.Equal(t, ErrResolutionMismatch, err)
+}
+
+func TestCellToLocalIJ(t *testing.T) {
+	t.Run("roundTrip", func(t *testing.T) {
+		origin := H3Index(0x8928308280fffff)
+		disk, err := GridDisk(origin, 8)
+		require.NoError(t, err)
+
+		originIJ, err := CellToLocalIJ(origin, origin)
+		require.NoError(t, err)
+
+		for _, h := range disk {
+			ij, err := CellToLocalIJ(origin, h)
+			require.NoError(t, err)
+
+			out, err := LocalIJToCell(origin, ij)
+			require.NoError(t, err)
+			require.Equal(t, h, out, "round trip of %x", h)
+
+			// neighbors of the origin are one step away in ij
+			if h3IndexesAreNeighbors(origin, h) == 1 {
+				di, dj := ij.I-originIJ.I, ij.J-originIJ.J
+				require.Contains(t, [][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}, {1, 1}, {-1, -1}}, [2]int{di, dj})
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := CellToLocalIJ(H3_INVALID_INDEX, 0x8928308280fffff)
+		require.Equal(t, ErrInvalidIndex, err)
+
+		_, err = CellToLocalIJ(0x8928308280fffff, 0x85283473fffffff)
+		require.Equal(t, ErrResolutionMismatch, err)
+
+		res0 := make([]H3Index, res0IndexCount())
+		getRes0Indexes(res0)
+		_, err = CellToLocalIJ(res0[0], res0[121])
+		require.Equal(t, ErrFailed, err, "base cells are not neighbors")
+
+		_, err = LocalIJToCell(0x8928308280fffff, CoordIJ{I: 1 << 20, J: -(1 << 20)})
+		require.Equal(t, ErrFailed, err, "too far from the origin")
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 1, 4, 0)
+
+		ij, err := CellToLocalIJ(pentagon, pentagon)
+		require.NoError(t, err)
+
+		// the k axis is deleted around the pentagon
+		var ijk CoordIJK
+		ijToIjk(&ij, &ijk)
+		_neighbor(&ijk, K_AXES_DIGIT)
+		var deleted CoordIJ
+		ijkToIj(&ijk, &deleted)
+
+		_, err = LocalIJToCell(pentagon, deleted)
+		require.Equal(t, ErrPentagon, err)
+	})
+}
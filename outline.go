@@ -0,0 +1,320 @@
+package h3
+
+/**
+ * @brief A vertex of the grid, identified by the three cells meeting at it in
+ * ascending order
+ */
+type topoVertex [3]H3Index
+
+/**
+ * _topoVertexOf returns the vertex where three cells meet.
+ */
+func _topoVertexOf(a H3Index, b H3Index, c H3Index) topoVertex {
+	if a > b {
+		a, b = b, a
+	}
+	if b > c {
+		b, c = c, b
+	}
+	if a > b {
+		a, b = b, a
+	}
+	return topoVertex{a, b, c}
+}
+
+/**
+ * @brief An edge of the outline of a set of cells, with the cell of the set
+ * on its left
+ */
+type outlineEdge struct {
+	cell   H3Index    ///< cell of the set
+	vertex int        ///< topological vertex number of the start in the cell
+	to     topoVertex ///< end of the edge
+}
+
+/**
+ * _cellVertexNeighbors finds the neighbors of a cell in the order of its
+ * topological vertexes: neighbors[v] is across the edge starting at vertex v.
+ *
+ * @param h The cell.
+ * @param neighbors Output array with room for NUM_HEX_VERTS cells.
+ * @return The number of vertexes of the cell.
+ */
+func _cellVertexNeighbors(h H3Index, neighbors []H3Index) int {
+	isPentagon := h3IsPentagon(h)
+	rotations := vertexRotations(h)
+
+	numVerts := NUM_HEX_VERTS
+	if isPentagon {
+		numVerts = NUM_PENT_VERTS
+	}
+	for dir := K_AXES_DIGIT; dir < NUM_DIGITS; dir++ {
+		var v int
+		if isPentagon {
+			if dir == K_AXES_DIGIT {
+				continue
+			}
+			v = (directionToVertexNumPent[dir] + NUM_PENT_VERTS - rotations) % NUM_PENT_VERTS
+		} else {
+			v = (directionToVertexNumHex[dir] + NUM_HEX_VERTS - rotations) % NUM_HEX_VERTS
+		}
+		rotations := 0
+		neighbors[v] = h3NeighborRotations(h, dir, &rotations)
+	}
+	return numVerts
+}
+
+/**
+ * @brief The edges of the outline of a set of cells, keyed by their start
+ */
+type outlineGraph struct {
+	edges  map[topoVertex]outlineEdge
+	starts []topoVertex ///< starts of the edges in the order they were found
+}
+
+/**
+ * _addCellToOutline adds the edges of a cell which are not shared with
+ * another cell of the set.
+ *
+ * @param graph The graph to add to.
+ * @param h The cell.
+ * @param contains Whether a cell is in the set.
+ */
+func _addCellToOutline(graph *outlineGraph, h H3Index, contains func(H3Index) bool) {
+	var neighbors [NUM_HEX_VERTS]H3Index
+	numVerts := _cellVertexNeighbors(h, neighbors[:])
+
+	for v := 0; v < numVerts; v++ {
+		if contains(neighbors[v]) {
+			continue
+		}
+		prev := neighbors[(v+numVerts-1)%numVerts]
+		next := neighbors[(v+1)%numVerts]
+		from := _topoVertexOf(h, prev, neighbors[v])
+		graph.edges[from] = outlineEdge{cell: h, vertex: v, to: _topoVertexOf(h, neighbors[v], next)}
+		graph.starts = append(graph.starts, from)
+	}
+}
+
+/**
+ * _outlineGraphToGeofences walks the loops of an outline graph, and only
+ * then computes the coordinates of their vertexes, including the distortion
+ * vertexes of edges crossing icosahedron edges.
+ *
+ * @param graph The graph, which is emptied.
+ * @param budget Checked for each edge walked, with the memory of the
+ *               vertexes reserved, or nil. The loops are left incomplete
+ *               when it runs out.
+ * @return One geofence for each loop of the graph.
+ */
+func _outlineGraphToGeofences(graph *outlineGraph, budget *workBudget) []Geofence {
+	var loops []Geofence
+	var gb GeoBoundary
+	var fijk FaceIJK
+
+	for _, start := range graph.starts {
+		if _, ok := graph.edges[start]; !ok {
+			continue // already part of a loop
+		}
+
+		var verts []GeoCoord
+		for at := start; ; {
+			edge, ok := graph.edges[at]
+			if !ok {
+				break
+			}
+			delete(graph.edges, at)
+
+			// both topological vertexes of the edge, and possibly a
+			// distortion vertex, of which the end is left to the next edge
+			_h3ToFaceIjk(edge.cell, &fijk)
+			_faceIjkToGeoBoundary(&fijk, H3_GET_RESOLUTION(edge.cell), h3IsPentagon(edge.cell), edge.vertex, 2, &gb)
+			verts = append(verts, gb.Verts[:gb.numVerts-1]...)
+			at = edge.to
+			if !budget.reserve(GEO_COORD_BYTES*float64(gb.numVerts-1)) || !budget.check(0) {
+				return loops
+			}
+		}
+		loops = append(loops, NewGeofence(verts))
+	}
+	graph.starts = nil
+	return loops
+}
+
+/**
+ * _outlineGraphToLinkedGeo walks the loops of an outline graph into a linked
+ * polygon.
+ *
+ * @param graph The graph, which is emptied.
+ * @param out Output polygon, with one loop for each loop of the graph.
+ */
+func _outlineGraphToLinkedGeo(graph *outlineGraph, out *LinkedGeoPolygon) {
+	*out = LinkedGeoPolygon{}
+	for _, loop := range _outlineGraphToGeofences(graph, nil) {
+		linked := addNewLinkedLoop(out)
+		for i := range loop.verts {
+			addLinkedCoord(linked, &loop.verts[i])
+		}
+	}
+}
+
+/**
+ * _geofencesToMultiPolygon arranges loops into polygons following GeoJSON
+ * MultiPolygon rules, as normalizeMultiPolygon does for a linked polygon:
+ * counter-clockwise loops are outer loops, in their order, and each
+ * clockwise loop is a hole of the innermost outer loop containing it.
+ *
+ * @param loops The loops.
+ * @param budget Checked for each test of a hole against an outer loop, or
+ *               nil. The holes are left unassigned when it runs out.
+ * @return The polygons, and NORMALIZATION_SUCCESS or
+ *         NORMALIZATION_ERR_UNASSIGNED_HOLES if a hole was dropped.
+ */
+func _geofencesToMultiPolygon(loops []Geofence, budget *workBudget) (GeoMultiPolygon, int) {
+	if len(loops) <= 1 {
+		polygons := make([]GeoPolygon, len(loops))
+		for i := range loops {
+			polygons[i] = NewGeoPolygon(loops[i])
+		}
+		return NewGeoMultiPolygon(polygons...), NORMALIZATION_SUCCESS
+	}
+
+	var polygons []GeoPolygon
+	var bboxes []BBox
+	var holes []int
+	for i := range loops {
+		if isClockwise(&loops[i]) {
+			holes = append(holes, i)
+			continue
+		}
+		polygons = append(polygons, NewGeoPolygon(loops[i]))
+		bboxes = append(bboxes, BBox{})
+		bboxFrom(&loops[i], &bboxes[len(bboxes)-1])
+	}
+
+	resultCode := NORMALIZATION_SUCCESS
+	var candidates []int
+	for _, i := range holes {
+		// We are guaranteed not to overlap, so just test the first point
+		candidates = candidates[:0]
+		for p := range polygons {
+			if !budget.check(0) {
+				return NewGeoMultiPolygon(polygons...), NORMALIZATION_ERR_UNASSIGNED_HOLES
+			}
+			if pointInside(&polygons[p].geofence, &bboxes[p], &loops[i].verts[0]) {
+				candidates = append(candidates, p)
+			}
+		}
+		if len(candidates) == 0 {
+			resultCode = NORMALIZATION_ERR_UNASSIGNED_HOLES
+			continue
+		}
+
+		// The most deeply nested container is the immediate parent
+		parent, max := candidates[0], -1
+		for _, p := range candidates {
+			count := 0
+			for _, q := range candidates {
+				if !budget.check(0) {
+					return NewGeoMultiPolygon(polygons...), NORMALIZATION_ERR_UNASSIGNED_HOLES
+				}
+				if q != p && pointInside(&polygons[q].geofence, &bboxes[q], &polygons[p].geofence.verts[0]) {
+					count++
+				}
+			}
+			if count > max {
+				parent, max = p, count
+			}
+		}
+		polygons[parent].holes = append(polygons[parent].holes, loops[i])
+		polygons[parent].numHoles++
+	}
+	return NewGeoMultiPolygon(polygons...), resultCode
+}
+
+/**
+ * _h3SetToOutlineGraph finds the edges of the outline of a set of cells of a
+ * single resolution. Edges are identified by the cells meeting at their
+ * vertexes, so no coordinates are compared.
+ *
+ * @param h3Set Cells of a single resolution; duplicates are ignored.
+ * @param graph Output graph.
+ * @param budget Checked for each cell, or nil. The graph is left incomplete
+ *               when it runs out.
+ */
+func _h3SetToOutlineGraph(h3Set []H3Index, graph *outlineGraph, budget *workBudget) {
+	if !budget.reserve(OUTLINE_BYTES_PER_CELL * float64(len(h3Set))) {
+		return
+	}
+	added := make(map[H3Index]bool, len(h3Set))
+	for _, h := range h3Set {
+		added[h] = false
+	}
+	contains := func(h H3Index) bool {
+		_, ok := added[h]
+		return ok
+	}
+
+	*graph = outlineGraph{edges: map[topoVertex]outlineEdge{}}
+	for _, h := range h3Set {
+		if !added[h] {
+			added[h] = true
+			numEdges := len(graph.starts)
+			_addCellToOutline(graph, h, contains)
+			if !budget.reserve(OUTLINE_BYTES_PER_EDGE*float64(len(graph.starts)-numEdges)) || !budget.check(0) {
+				return
+			}
+		}
+	}
+}
+
+/**
+ * _cellSetToOutlineGraph finds the edges of the outline of the area covered
+ * by a set of cells of mixed resolutions, drawn along the cells of the given
+ * resolution. Only the cells along the outline are expanded to it: no
+ * descendant of a cell whose neighbors are all covered is on the outline.
+ *
+ * @param set The covered area.
+ * @param res The resolution of the outline, no coarser than any cell of the set.
+ * @param graph Output graph.
+ */
+func _cellSetToOutlineGraph(set *CellSet, res int, graph *outlineGraph) {
+	contains := func(h H3Index) bool {
+		_, ok := set._covering(h)
+		return ok
+	}
+
+	*graph = outlineGraph{edges: map[topoVertex]outlineEdge{}}
+	for _, h := range set.Cells() {
+		_addCoverToOutline(graph, h, res, contains)
+	}
+}
+
+/**
+ * _addCoverToOutline adds the outline edges of the descendants of a covered
+ * cell at the given resolution, descending only next to uncovered cells.
+ *
+ * @param graph The graph to add to.
+ * @param h The covered cell.
+ * @param res The resolution of the outline.
+ * @param contains Whether a cell is covered.
+ */
+func _addCoverToOutline(graph *outlineGraph, h H3Index, res int, contains func(H3Index) bool) {
+	if H3_GET_RESOLUTION(h) == res {
+		_addCellToOutline(graph, h, contains)
+		return
+	}
+
+	var neighbors [NUM_HEX_VERTS]H3Index
+	numVerts := _cellVertexNeighbors(h, neighbors[:])
+	interior := true
+	for v := 0; v < numVerts && interior; v++ {
+		interior = contains(neighbors[v])
+	}
+	if interior {
+		return
+	}
+	for _, c := range _directChildren(h) {
+		_addCoverToOutline(graph, c, res, contains)
+	}
+}
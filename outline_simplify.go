@@ -0,0 +1,396 @@
+package h3
+
+import (
+	"container/heap"
+	"math"
+)
+
+/** Simplification applied by SimplifyMultiPolygon. */
+type SimplifyMode int
+
+/** Simplification modes */
+const (
+	SIMPLIFY_COLLINEAR       SimplifyMode = iota ///< only remove vertices on the line between their neighbors
+	SIMPLIFY_DOUGLAS_PEUCKER                     ///< keep the vertices farther than the tolerance from the simplified loop
+	SIMPLIFY_VISVALINGAM                         ///< remove vertices spanning a triangle smaller than the tolerance squared
+	SIMPLIFY_SMOOTH                              ///< cut the corners of the hexagon edges, then Douglas-Peucker
+)
+
+/**
+ * Number of times the tolerance of a loop is halved when its simplification
+ * breaks the topology, before the loop is left unsimplified.
+ */
+const SIMPLIFY_MAX_RETRIES = 4
+
+/**
+ * @brief A loop of a multi polygon being simplified
+ */
+type simplifiedLoop struct {
+	polygon    int      ///< index of the polygon of the loop
+	original   Geofence ///< loop without its collinear vertices
+	simplified Geofence
+	tolerance  float64 ///< tolerance of simplified, in radians
+	retries    int
+}
+
+/**
+ * _loopPlane projects the vertices of a loop to a plane where distances
+ * approximate those on the sphere: longitudes are scaled by the cosine of
+ * the latitude at the middle of the loop.
+ *
+ * @param verts The vertices of the loop.
+ * @return The projected vertices, in radians.
+ */
+func _loopPlane(verts []GeoCoord) []Vec2d {
+	loop := NewGeofence(verts)
+	var bbox BBox
+	bboxFrom(&loop, &bbox)
+	isTransmeridian := bboxIsTransmeridian(&bbox)
+	scale := math.Cos((bbox.north + bbox.south) / 2)
+
+	pts := make([]Vec2d, len(verts))
+	for i, v := range verts {
+		pts[i] = Vec2d{NORMALIZE_LON(v.Lon, isTransmeridian) * scale, v.Lat}
+	}
+	return pts
+}
+
+/**
+ * _v2dSegmentDist returns the distance from a point to a segment.
+ */
+func _v2dSegmentDist(a *Vec2d, b *Vec2d, p *Vec2d) float64 {
+	dx, dy := b.x-a.x, b.y-a.y
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = math.Max(0, math.Min(1, ((p.x-a.x)*dx+(p.y-a.y)*dy)/l))
+	}
+	return math.Hypot(p.x-(a.x+t*dx), p.y-(a.y+t*dy))
+}
+
+/**
+ * _douglasPeucker selects the vertices of a closed loop which are farther
+ * than the tolerance from the simplified loop. The first vertex and the one
+ * farthest from it are always kept.
+ *
+ * @param pts The projected vertices of the loop.
+ * @param tolerance The tolerance in radians.
+ * @return Whether each vertex is kept.
+ */
+func _douglasPeucker(pts []Vec2d, tolerance float64) []bool {
+	n := len(pts)
+	keep := make([]bool, n)
+	keep[0] = true
+
+	far, farDist := 0, -1.0
+	for i := 1; i < n; i++ {
+		if d := math.Hypot(pts[i].x-pts[0].x, pts[i].y-pts[0].y); d > farDist {
+			far, farDist = i, d
+		}
+	}
+	keep[far] = true
+
+	// ranges of vertex indexes, where n stands for the first vertex again
+	stack := [][2]int{{0, far}, {far, n}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		a, b := &pts[r[0]], &pts[r[1]%n]
+
+		split, splitDist := -1, tolerance
+		for i := r[0] + 1; i < r[1]; i++ {
+			if d := _v2dSegmentDist(a, b, &pts[i]); d > splitDist {
+				split, splitDist = i, d
+			}
+		}
+		if split >= 0 {
+			keep[split] = true
+			stack = append(stack, [2]int{r[0], split}, [2]int{split, r[1]})
+		}
+	}
+	return keep
+}
+
+/**
+ * @brief Vertex in the queue of the Visvalingam simplification
+ */
+type visvalingamVertex struct {
+	index   int
+	area    float64
+	version int ///< version of the vertex when it was queued
+}
+
+type visvalingamQueue []visvalingamVertex
+
+func (q visvalingamQueue) Len() int            { return len(q) }
+func (q visvalingamQueue) Less(i, j int) bool  { return q[i].area < q[j].area }
+func (q visvalingamQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *visvalingamQueue) Push(x interface{}) { *q = append(*q, x.(visvalingamVertex)) }
+func (q *visvalingamQueue) Pop() interface{} {
+	old := *q
+	v := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return v
+}
+
+/**
+ * _visvalingam selects the vertices of a closed loop by repeatedly removing
+ * the vertex spanning the smallest triangle with its neighbors, while that
+ * triangle is smaller than the tolerance squared. At least 3 vertices are
+ * kept.
+ *
+ * @param pts The projected vertices of the loop.
+ * @param tolerance The tolerance in radians.
+ * @return Whether each vertex is kept.
+ */
+func _visvalingam(pts []Vec2d, tolerance float64) []bool {
+	n := len(pts)
+	keep := make([]bool, n)
+	prev := make([]int, n)
+	next := make([]int, n)
+	version := make([]int, n)
+	area := func(i int) float64 {
+		return math.Abs(_v2dCross(&pts[prev[i]], &pts[i], &pts[next[i]])) / 2
+	}
+
+	q := make(visvalingamQueue, 0, n)
+	for i := range pts {
+		keep[i] = true
+		prev[i] = (i + n - 1) % n
+		next[i] = (i + 1) % n
+	}
+	for i := range pts {
+		q = append(q, visvalingamVertex{index: i, area: area(i)})
+	}
+	heap.Init(&q)
+
+	threshold := tolerance * tolerance
+	for remaining := n; remaining > 3 && q.Len() > 0; {
+		v := heap.Pop(&q).(visvalingamVertex)
+		if v.version != version[v.index] {
+			continue
+		}
+		if v.area >= threshold {
+			break
+		}
+
+		keep[v.index] = false
+		remaining--
+		p, nx := prev[v.index], next[v.index]
+		next[p], prev[nx] = nx, p
+		for _, i := range []int{p, nx} {
+			version[i]++
+			heap.Push(&q, visvalingamVertex{index: i, area: area(i), version: version[i]})
+		}
+	}
+	return keep
+}
+
+/**
+ * _smoothLoop replaces the vertices of a loop by the midpoints of its edges,
+ * which turns the stair-steps of hexagon edges into straight lines.
+ */
+func _smoothLoop(verts []GeoCoord) []GeoCoord {
+	loop := NewGeofence(verts)
+	var bbox BBox
+	bboxFrom(&loop, &bbox)
+	isTransmeridian := bboxIsTransmeridian(&bbox)
+
+	out := make([]GeoCoord, len(verts))
+	for i := range verts {
+		a, b := verts[i], verts[(i+1)%len(verts)]
+		lon := (NORMALIZE_LON(a.Lon, isTransmeridian) + NORMALIZE_LON(b.Lon, isTransmeridian)) / 2
+		if lon > M_PI {
+			lon -= M_2PI
+		}
+		out[i] = GeoCoord{Lat: (a.Lat + b.Lat) / 2, Lon: lon}
+	}
+	return out
+}
+
+/**
+ * _simplifyLoop simplifies a single loop.
+ *
+ * @param loop The loop to simplify.
+ * @param mode The simplification mode.
+ * @param tolerance The tolerance in radians.
+ * @return The simplified loop, which may have fewer than 3 vertices.
+ */
+func _simplifyLoop(loop *Geofence, mode SimplifyMode, tolerance float64) Geofence {
+	verts := loop.Verts()
+	if len(verts) < 4 {
+		return *loop
+	}
+	if mode == SIMPLIFY_SMOOTH {
+		verts = _smoothLoop(verts)
+	}
+
+	pts := _loopPlane(verts)
+	var keep []bool
+	switch mode {
+	case SIMPLIFY_VISVALINGAM:
+		keep = _visvalingam(pts, tolerance)
+	default:
+		keep = _douglasPeucker(pts, tolerance)
+	}
+
+	out := make([]GeoCoord, 0, len(verts))
+	for i, v := range verts {
+		if keep[i] {
+			out = append(out, v)
+		}
+	}
+	return NewGeofence(out)
+}
+
+/**
+ * _simplifyConflicts finds the loops whose simplification changed the
+ * topology of the multi polygon: loops which collapsed or changed their
+ * orientation, edges which meet, holes which left their geofence or entered
+ * another hole, and polygons which entered another polygon.
+ *
+ * @param loops The loops of the multi polygon, geofences before their holes.
+ * @return The indexes of the conflicting loops.
+ */
+func _simplifyConflicts(loops []simplifiedLoop) map[int]bool {
+	conflicts := map[int]bool{}
+	geofences := make([]*Geofence, len(loops))
+	bboxes := make([]BBox, len(loops))
+	isTransmeridian := false
+	for i := range loops {
+		l := &loops[i]
+		geofences[i] = &l.simplified
+		if l.simplified.numVerts < 3 || isClockwise(&l.simplified) != isClockwise(&l.original) {
+			conflicts[i] = true
+			continue
+		}
+		bboxFrom(&l.simplified, &bboxes[i])
+		if bboxIsTransmeridian(&bboxes[i]) {
+			isTransmeridian = true
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts
+	}
+
+	for _, err := range _polygonIntersections(geofences, isTransmeridian) {
+		conflicts[err.Loop] = true
+		conflicts[err.OtherLoop] = true
+	}
+	if len(conflicts) > 0 {
+		return conflicts
+	}
+
+	// whether a vertex of loop i is inside the polygon starting at loop p,
+	// and otherwise the hole of the polygon it is in, or -1
+	inside := func(p int, i int) (bool, int) {
+		g := &loops[i].simplified.verts[0]
+		if !pointInside(geofences[p], &bboxes[p], g) {
+			return false, -1
+		}
+		for h := p + 1; h < len(loops) && loops[h].polygon == loops[p].polygon; h++ {
+			if h != i && pointInside(geofences[h], &bboxes[h], g) {
+				return false, h
+			}
+		}
+		return true, -1
+	}
+
+	for i := range loops {
+		for p := range loops {
+			if p == i || (p > 0 && loops[p-1].polygon == loops[p].polygon) {
+				continue // not the geofence of another polygon
+			}
+			in, hole := inside(p, i)
+			if loops[p].polygon == loops[i].polygon {
+				// a hole must be inside its geofence, and not inside another
+				// hole
+				if !in {
+					conflicts[i], conflicts[p] = true, true
+					if hole >= 0 {
+						conflicts[hole] = true
+					}
+				}
+			} else if in {
+				conflicts[i], conflicts[p] = true, true
+			}
+		}
+	}
+	return conflicts
+}
+
+/**
+ * SimplifyMultiPolygon reduces the number of vertices of outlines such as
+ * those of CellsToMultiPolygon.
+ *
+ * Collinear vertices are always removed. The other modes are applied to
+ * each loop separately, with distances measured in the plane of longitude
+ * and latitude scaled to the middle of the loop. The topology is preserved:
+ * when a simplified loop collapses, meets another loop, or moves a hole or
+ * polygon in or out of another one, the loops involved are simplified again
+ * with half the tolerance, and after SIMPLIFY_MAX_RETRIES attempts they are
+ * left with only their collinear vertices removed.
+ *
+ * @param multiPolygon The outlines to simplify, which are not modified.
+ * @param mode The simplification mode.
+ * @param tolerance The tolerance, ignored for SIMPLIFY_COLLINEAR.
+ * @param unit The unit of the tolerance.
+ * @return The simplified outlines, or ErrDomain for an unknown mode or unit
+ *         or a negative tolerance.
+ */
+func SimplifyMultiPolygon(multiPolygon GeoMultiPolygon, mode SimplifyMode, tolerance float64, unit DistanceUnit) (GeoMultiPolygon, error) {
+	tol := _unitToRads(tolerance, unit)
+	if mode < SIMPLIFY_COLLINEAR || mode > SIMPLIFY_SMOOTH || math.IsNaN(tol) || tol < 0 {
+		return GeoMultiPolygon{}, ErrDomain
+	}
+
+	var loops []simplifiedLoop
+	for p, polygon := range multiPolygon.Polygons() {
+		for l := -1; l < polygon.numHoles; l++ {
+			loop := &polygon.geofence
+			if l >= 0 {
+				loop = &polygon.holes[l]
+			}
+			original := _simplifyLoop(loop, SIMPLIFY_COLLINEAR, EPSILON_RAD)
+			loops = append(loops, simplifiedLoop{polygon: p, original: original, simplified: original})
+		}
+	}
+
+	if mode != SIMPLIFY_COLLINEAR {
+		for i := range loops {
+			loops[i].tolerance = tol
+			loops[i].simplified = _simplifyLoop(&loops[i].original, mode, tol)
+		}
+		for {
+			changed := false
+			for i := range _simplifyConflicts(loops) {
+				l := &loops[i]
+				if l.retries > SIMPLIFY_MAX_RETRIES {
+					continue // already the original loop
+				}
+				changed = true
+				l.retries++
+				if l.retries > SIMPLIFY_MAX_RETRIES {
+					l.simplified = l.original
+				} else {
+					l.tolerance /= 2
+					l.simplified = _simplifyLoop(&l.original, mode, l.tolerance)
+				}
+			}
+			if !changed {
+				break
+			}
+		}
+	}
+
+	polygons := make([]GeoPolygon, 0, multiPolygon.numPolygons)
+	for i := 0; i < len(loops); {
+		p := loops[i].polygon
+		geofence := loops[i].simplified
+		var holes []Geofence
+		for i++; i < len(loops) && loops[i].polygon == p; i++ {
+			holes = append(holes, loops[i].simplified)
+		}
+		polygons = append(polygons, NewGeoPolygon(geofence, holes...))
+	}
+	return NewGeoMultiPolygon(polygons...), nil
+}
@@ -0,0 +1,137 @@
+package h3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimplifyMultiPolygon(t *testing.T) {
+	countVerts := func(m GeoMultiPolygon) int {
+		n := 0
+		for _, polygon := range m.Polygons() {
+			n += polygon.geofence.numVerts
+			for _, hole := range polygon.Holes() {
+				n += hole.numVerts
+			}
+		}
+		return n
+	}
+	// checks the loops of all polygons have no crossings and that holes and
+	// polygons are where they were
+	requireTopology := func(t *testing.T, before GeoMultiPolygon, after GeoMultiPolygon) {
+		require.Len(t, after.Polygons(), len(before.Polygons()))
+		var loops []*Geofence
+		for i, polygon := range after.Polygons() {
+			require.Len(t, polygon.Holes(), len(before.Polygons()[i].Holes()))
+			require.Empty(t, ValidatePolygon(polygon))
+			loops = append(loops, &after.polygons[i].geofence)
+			for j := range polygon.Holes() {
+				loops = append(loops, &after.polygons[i].holes[j])
+			}
+		}
+		require.Empty(t, _polygonIntersections(loops, false))
+		for i, p := range after.Polygons() {
+			bboxes := make([]BBox, p.numHoles+1)
+			bboxesFromGeoPolygon(&p, bboxes)
+			for j, q := range after.Polygons() {
+				if i != j {
+					require.False(t, pointInsidePolygon(&p, bboxes, &q.geofence.verts[0]))
+				}
+			}
+		}
+	}
+
+	cells, err := PolygonToCells(NewGeoPolygon(NewGeofence(sfVerts)), 10)
+	require.NoError(t, err)
+	outline, err := CellsToMultiPolygon(cells)
+	require.NoError(t, err)
+	require.Len(t, outline.Polygons(), 1)
+
+	t.Run("modes", func(t *testing.T) {
+		for _, mode := range []SimplifyMode{SIMPLIFY_DOUGLAS_PEUCKER, SIMPLIFY_VISVALINGAM, SIMPLIFY_SMOOTH} {
+			simplified, err := SimplifyMultiPolygon(outline, mode, 100, UNIT_M)
+			require.NoError(t, err)
+			requireTopology(t, outline, simplified)
+			require.True(t, countVerts(simplified)*5 < countVerts(outline), "mode %d: %d of %d vertices",
+				mode, countVerts(simplified), countVerts(outline))
+
+			// the simplified outline covers nearly the same cells
+			filled, err := PolygonToCells(simplified.Polygons()[0], 10)
+			require.NoError(t, err)
+			set := map[H3Index]bool{}
+			for _, h := range cells {
+				set[h] = true
+			}
+			diff := 0
+			for _, h := range filled {
+				if !set[h] {
+					diff++
+				}
+				delete(set, h)
+			}
+			diff += len(set)
+			require.True(t, diff*20 < len(cells), "mode %d: %d of %d cells differ", mode, diff, len(cells))
+		}
+	})
+
+	t.Run("collinear", func(t *testing.T) {
+		square := NewGeoMultiPolygon(NewGeoPolygon(NewGeofence([]GeoCoord{
+			{0, 0}, {0, 0.5}, {0, 1}, {1, 1}, {1, 0}, {0.5, 0},
+		})))
+		simplified, err := SimplifyMultiPolygon(square, SIMPLIFY_COLLINEAR, 0, UNIT_M)
+		require.NoError(t, err)
+		require.Equal(t, []GeoCoord{{0, 0}, {0, 1}, {1, 1}, {1, 0}}, simplified.Polygons()[0].Geofence().Verts())
+
+		simplified, err = SimplifyMultiPolygon(outline, SIMPLIFY_COLLINEAR, 0, UNIT_M)
+		require.NoError(t, err)
+		require.Equal(t, outline, simplified, "cell outlines have no collinear vertices")
+	})
+
+	t.Run("topology", func(t *testing.T) {
+		// a ring with a small hole, next to another disk
+		disk, err := GridDisk(0x8928308280fffff, 3)
+		require.NoError(t, err)
+		center, err := GridDisk(0x8928308280fffff, 0)
+		require.NoError(t, err)
+		ring := subtractCells(disk, center)
+		other, err := GridRing(0x8928308280fffff, 6)
+		require.NoError(t, err)
+		other, err = GridDisk(other[0], 1)
+		require.NoError(t, err)
+
+		m, err := CellsToMultiPolygon(append(ring, other...))
+		require.NoError(t, err)
+		require.Len(t, m.Polygons(), 2)
+
+		for _, mode := range []SimplifyMode{SIMPLIFY_DOUGLAS_PEUCKER, SIMPLIFY_VISVALINGAM, SIMPLIFY_SMOOTH} {
+			simplified, err := SimplifyMultiPolygon(m, mode, 10, UNIT_KM)
+			require.NoError(t, err)
+			requireTopology(t, m, simplified)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := SimplifyMultiPolygon(outline, SIMPLIFY_DOUGLAS_PEUCKER, -1, UNIT_M)
+		require.Equal(t, ErrDomain, err)
+		_, err = SimplifyMultiPolygon(outline, SIMPLIFY_DOUGLAS_PEUCKER, 1, DistanceUnit(7))
+		require.Equal(t, ErrDomain, err)
+		_, err = SimplifyMultiPolygon(outline, SimplifyMode(7), 1, UNIT_M)
+		require.Equal(t, ErrDomain, err)
+	})
+}
+
+// subtractCells returns the cells of a which are not in b
+func subtractCells(a []H3Index, b []H3Index) []H3Index {
+	exclude := map[H3Index]bool{}
+	for _, h := range b {
+		exclude[h] = true
+	}
+	var out []H3Index
+	for _, h := range a {
+		if !exclude[h] {
+			out = append(out, h)
+		}
+	}
+	return out
+}
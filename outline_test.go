@@ -0,0 +1,245 @@
+package h3
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_vertexNumForDirection(t *testing.T) {
+	// the vertexes of an edge are shared by the boundaries of both cells
+	requireEdge := func(t *testing.T, edge H3Index) {
+		var gb, origin, destination GeoBoundary
+		getH3UnidirectionalEdgeBoundary(edge, &gb)
+		require.True(t, gb.numVerts == 2 || gb.numVerts == 3, "edge %x", edge)
+		h3ToGeoBoundary(getOriginH3IndexFromUnidirectionalEdge(edge), &origin)
+		h3ToGeoBoundary(getDestinationH3IndexFromUnidirectionalEdge(edge), &destination)
+
+		threshold := edgeLengthKm(H3_GET_RESOLUTION(edge)) / EARTH_RADIUS_KM * 1e-3
+		for _, boundary := range []*GeoBoundary{&origin, &destination} {
+			for i := 0; i < gb.numVerts; i++ {
+				found := false
+				for j := 0; j < boundary.numVerts; j++ {
+					found = found || geoAlmostEqualThreshold(&gb.Verts[i], &boundary.Verts[j], threshold)
+				}
+				require.True(t, found, "edge %x vertex %d", edge, i)
+			}
+		}
+	}
+
+	var cells []H3Index
+	for _, res := range []int{0, 1, 2, 5, 10, 15} {
+		for baseCell := 0; baseCell < NUM_BASE_CELLS; baseCell++ {
+			if res > 2 && !_isBaseCellPentagon(baseCell) {
+				continue
+			}
+			var h H3Index
+			setH3Index(&h, res, baseCell, 0)
+			disk, err := GridDisk(h, 2)
+			require.NoError(t, err)
+			cells = append(cells, disk...)
+		}
+	}
+	for _, h := range cells {
+		edges := make([]H3Index, 6)
+		getH3UnidirectionalEdgesFromHexagon(h, edges)
+		for _, edge := range edges {
+			if edge != H3_INVALID_INDEX {
+				requireEdge(t, edge)
+			}
+		}
+	}
+
+	require.Equal(t, INVALID_VERTEX_NUM, vertexNumForDirection(0x8928308280fffff, CENTER_DIGIT))
+	require.Equal(t, INVALID_VERTEX_NUM, vertexNumForDirection(0x8928308280fffff, INVALID_DIGIT))
+	var pentagon H3Index
+	setH3Index(&pentagon, 5, 4, 0)
+	require.Equal(t, INVALID_VERTEX_NUM, vertexNumForDirection(pentagon, K_AXES_DIGIT))
+}
+
+func Test_h3SetToOutlineGraph(t *testing.T) {
+	// the sizes of the loops of each polygon, outer loop first
+	loopSizes := func(polygon *LinkedGeoPolygon) [][]int {
+		var out [][]int
+		for p := polygon; p != nil; p = p.next {
+			var sizes []int
+			for loop := p.first; loop != nil; loop = loop.next {
+				sizes = append(sizes, countLinkedCoords(loop))
+			}
+			if len(sizes) > 0 {
+				sort.Ints(sizes[1:])
+			}
+			out = append(out, sizes)
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i][0] != out[j][0] {
+				return out[i][0] < out[j][0]
+			}
+			return len(out[i]) < len(out[j])
+		})
+		return out
+	}
+	outline := func(cells []H3Index) *LinkedGeoPolygon {
+		var polygon LinkedGeoPolygon
+		h3SetToLinkedGeo(cells, len(cells), &polygon)
+		return &polygon
+	}
+
+	t.Run("sameAsVertexGraph", func(t *testing.T) {
+		r := rand.New(rand.NewSource(44))
+		for i := 0; i < 50; i++ {
+			// a few overlapping disks around a random cell, at a random
+			// coarse resolution; at resolution 0 the vertex graph leaves
+			// spurious holes
+			var center GeoCoord
+			center.Lat = (r.Float64() - 0.5) * M_PI
+			center.Lon = (r.Float64()*2 - 1) * M_PI
+			origin := geoToH3(&center, 1+r.Intn(5))
+
+			var cells []H3Index
+			disk, err := GridDisk(origin, 4)
+			require.NoError(t, err)
+			for j := 0; j < 3; j++ {
+				part, err := GridDisk(disk[r.Intn(len(disk))], 1+r.Intn(2))
+				require.NoError(t, err)
+				cells = append(cells, part...)
+			}
+			cells = subtractCells(cells, disk[:1])
+			cells, err = mustCellSet(t, cells).Uncompact(H3_GET_RESOLUTION(origin))
+			require.NoError(t, err)
+
+			var expected LinkedGeoPolygon
+			var graph VertexGraph
+			h3SetToVertexGraph(cells, len(cells), &graph)
+			_vertexGraphToLinkedGeo(&graph, &expected)
+			normalizeMultiPolygon(&expected)
+
+			// the vertex graph leaves stray loops where distortion vertexes of
+			// neighbors on other faces did not match
+			var sizes [][]int
+			for _, polygon := range loopSizes(&expected) {
+				if polygon[0] > 2 {
+					sizes = append(sizes, polygon)
+				}
+			}
+			require.Equal(t, sizes, loopSizes(outline(cells)), "cells %x", cells)
+		}
+	})
+
+	t.Run("fine", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 15, 38, 0)
+		origins := []H3Index{
+			0x8f2830828052d25, // San Francisco
+			pentagon,
+			geoToH3(&GeoCoord{Lat: M_PI_2 - 0.01, Lon: 0.3}, 15), // near the pole
+			geoToH3(&GeoCoord{Lat: 0.2, Lon: M_PI - 1e-9}, 15),
+		}
+		for _, origin := range origins {
+			disk, err := GridDisk(origin, 3)
+			require.NoError(t, err)
+			ring, err := GridRing(origin, 1)
+			require.NoError(t, err)
+
+			sizes := loopSizes(outline(subtractCells(disk, ring)))
+			require.Len(t, sizes, 2, "origin %x", origin)
+			require.Len(t, sizes[0], 1, "the origin is a polygon of its own")
+			require.Len(t, sizes[1], 2, "the ring has one hole")
+		}
+	})
+
+	t.Run("duplicates", func(t *testing.T) {
+		disk, err := GridDisk(0x8928308280fffff, 2)
+		require.NoError(t, err)
+		require.Equal(t, [][]int{{30}}, loopSizes(outline(append(disk, disk...))))
+	})
+}
+
+func Test_geofencesToMultiPolygon(t *testing.T) {
+	// the same polygons as normalizing the loops in a linked polygon
+	requireSameAsLinkedGeo := func(t *testing.T, cells []H3Index) GeoMultiPolygon {
+		var graph outlineGraph
+		var polygon LinkedGeoPolygon
+		_h3SetToOutlineGraph(cells, &graph, nil)
+		_outlineGraphToLinkedGeo(&graph, &polygon)
+		expected := normalizeMultiPolygon(&polygon)
+
+		_h3SetToOutlineGraph(cells, &graph, nil)
+		multiPolygon, result := _geofencesToMultiPolygon(_outlineGraphToGeofences(&graph, nil), nil)
+		require.Equal(t, expected, result)
+		require.Equal(t, linkedGeoToMultiPolygon(&polygon), multiPolygon)
+		return multiPolygon
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		disk, err := GridDisk(0x8928308280fffff, 6)
+		require.NoError(t, err)
+		for _, k := range []int{1, 5} {
+			ring, err := GridRing(0x8928308280fffff, k)
+			require.NoError(t, err)
+			disk = subtractCells(disk, ring)
+		}
+		multiPolygon := requireSameAsLinkedGeo(t, disk)
+		require.Len(t, multiPolygon.Polygons(), 3)
+	})
+
+	t.Run("random", func(t *testing.T) {
+		r := rand.New(rand.NewSource(46))
+		for i := 0; i < 20; i++ {
+			origin := geoToH3(&GeoCoord{Lat: (r.Float64() - 0.5) * M_PI, Lon: (r.Float64()*2 - 1) * M_PI}, 1+r.Intn(8))
+			disk, err := GridDisk(origin, 6)
+			require.NoError(t, err)
+			requireSameAsLinkedGeo(t, subtractCells(disk, disk[r.Intn(len(disk)/2):len(disk)/2]))
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		disk, err := GridDisk(0x8928308280fffff, 6)
+		require.NoError(t, err)
+		ring, err := GridRing(0x8928308280fffff, 3)
+		require.NoError(t, err)
+		cells := subtractCells(disk, ring)
+		canceled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var graph outlineGraph
+		_h3SetToOutlineGraph(cells, &graph, nil)
+		budget := &workBudget{ctx: canceled}
+		_outlineGraphToGeofences(&graph, budget)
+		require.Equal(t, context.Canceled, budget.err)
+
+		_h3SetToOutlineGraph(cells, &graph, nil)
+		budget = &workBudget{ctx: context.Background(), opts: Options{MaxMemory: 100}}
+		_outlineGraphToGeofences(&graph, budget)
+		require.Equal(t, ErrMemoryLimit, budget.err)
+
+		_h3SetToOutlineGraph(cells, &graph, nil)
+		budget = &workBudget{ctx: context.Background()}
+		loops := _outlineGraphToGeofences(&graph, budget)
+		require.NoError(t, budget.err)
+		require.True(t, budget.reserved > 0)
+		require.Len(t, loops, 3)
+
+		budget = &workBudget{ctx: canceled}
+		_, result := _geofencesToMultiPolygon(loops, budget)
+		require.Equal(t, context.Canceled, budget.err)
+		require.Equal(t, NORMALIZATION_ERR_UNASSIGNED_HOLES, result)
+	})
+
+	t.Run("unassignedHole", func(t *testing.T) {
+		hole := NewGeofence([]GeoCoord{{0, 0}, {1, 0}, {1, 1}, {0, 1}})
+		outer := NewGeofence([]GeoCoord{{2, 2}, {2, 3}, {3, 3}, {3, 2}})
+		multiPolygon, result := _geofencesToMultiPolygon([]Geofence{hole, outer}, nil)
+		require.Equal(t, NORMALIZATION_ERR_UNASSIGNED_HOLES, result)
+		require.Equal(t, NewGeoMultiPolygon(NewGeoPolygon(outer)), multiPolygon)
+	})
+}
+
+func mustCellSet(t *testing.T, cells []H3Index) *CellSet {
+	set, err := NewCellSet(cells...)
+	require.NoError(t, err)
+	return set
+}
@@ -38,3 +38,76 @@ func pointInsidePolygon(geoPolygon *GeoPolygon, bboxes []BBox, coord *GeoCoord)
 
 	return contains
 }
+
+/**
+ * NewGeofence creates a geofence from its vertices. The loop is implicitly
+ * closed, so the last vertex should not repeat the first one.
+ *
+ * @param verts Vertices in radians
+ * @return      The geofence
+ */
+func NewGeofence(verts []GeoCoord) Geofence {
+	return Geofence{numVerts: len(verts), verts: verts}
+}
+
+/**
+ * Verts returns the vertices of the geofence.
+ */
+func (g Geofence) Verts() []GeoCoord {
+	return g.verts[:g.numVerts]
+}
+
+/**
+ * NewGeoPolygon creates a polygon from its exterior boundary and holes.
+ *
+ * @param geofence Exterior boundary of the polygon
+ * @param holes    Interior boundaries of the polygon
+ * @return         The polygon
+ */
+func NewGeoPolygon(geofence Geofence, holes ...Geofence) GeoPolygon {
+	return GeoPolygon{geofence: geofence, numHoles: len(holes), holes: holes}
+}
+
+/**
+ * _geoPolygonCopy returns a copy of a polygon which shares no vertices with
+ * it.
+ */
+func _geoPolygonCopy(p *GeoPolygon) GeoPolygon {
+	holes := make([]Geofence, p.numHoles)
+	for i := range holes {
+		holes[i] = _geofenceCopy(&p.holes[i])
+	}
+	return NewGeoPolygon(_geofenceCopy(&p.geofence), holes...)
+}
+
+func _geofenceCopy(g *Geofence) Geofence {
+	return NewGeofence(append([]GeoCoord(nil), g.verts[:g.numVerts]...))
+}
+
+/**
+ * Geofence returns the exterior boundary of the polygon.
+ */
+func (p GeoPolygon) Geofence() Geofence {
+	return p.geofence
+}
+
+/**
+ * Holes returns the interior boundaries of the polygon.
+ */
+func (p GeoPolygon) Holes() []Geofence {
+	return p.holes[:p.numHoles]
+}
+
+/**
+ * NewGeoMultiPolygon creates a multi polygon from its polygons.
+ */
+func NewGeoMultiPolygon(polygons ...GeoPolygon) GeoMultiPolygon {
+	return GeoMultiPolygon{numPolygons: len(polygons), polygons: polygons}
+}
+
+/**
+ * Polygons returns the polygons of the multi polygon.
+ */
+func (m GeoMultiPolygon) Polygons() []GeoPolygon {
+	return m.polygons[:m.numPolygons]
+}
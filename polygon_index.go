@@ -0,0 +1,191 @@
+package h3
+
+import (
+	"sort"
+	"sync"
+)
+
+/**
+ * @brief Cover of a polygon in a PolygonIndex
+ */
+type polygonIndexEntry struct {
+	prepared *PreparedPolygon
+	interior []H3Index ///< compacted interior cells
+}
+
+/**
+ * @brief In memory index of named polygons
+ *
+ * Every polygon is covered as by PreparedPolygon: its interior cells are
+ * compacted and stored in one map per resolution, from cell to the IDs of
+ * the polygons, and its boundary cells in a map of their own. A lookup
+ * checks the ancestors of the cell of the point in the interior maps, and
+ * tests the point against the polygons of its boundary cell only.
+ *
+ * Lookups may run concurrently with each other and with Add and Remove.
+ */
+type PolygonIndex struct {
+	res      int
+	mu       sync.RWMutex
+	polygons map[string]*polygonIndexEntry
+	interior [MAX_H3_RES + 1]map[H3Index][]string ///< interior cells by resolution
+	boundary map[H3Index][]string                 ///< boundary cells at res
+}
+
+/**
+ * NewPolygonIndex creates an empty index.
+ *
+ * @param res The resolution of the polygon covers, see PreparedPolygon.
+ * @return The index, or ErrDomain for an invalid resolution.
+ */
+func NewPolygonIndex(res int) (*PolygonIndex, error) {
+	if res < 0 || res > MAX_H3_RES {
+		return nil, ErrDomain
+	}
+
+	idx := &PolygonIndex{
+		res:      res,
+		polygons: map[string]*polygonIndexEntry{},
+		boundary: map[H3Index][]string{},
+	}
+	for r := range idx.interior {
+		idx.interior[r] = map[H3Index][]string{}
+	}
+	return idx, nil
+}
+
+/**
+ * Len returns the number of polygons in the index.
+ */
+func (idx *PolygonIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.polygons)
+}
+
+/**
+ * Add indexes a polygon. The cover is computed before the index is locked,
+ * so lookups are only held up while the cells are inserted.
+ *
+ * @param id The ID of the polygon.
+ * @param polygon The geofence and holes defining the area, in radians.
+ * @return ErrDuplicateInput when the ID is already used, or the errors of
+ *         NewPreparedPolygon.
+ */
+func (idx *PolygonIndex) Add(id string, polygon GeoPolygon) error {
+	prepared, err := NewPreparedPolygon(polygon, idx.res)
+	if err != nil {
+		return err
+	}
+
+	cells := make([]H3Index, 0, len(prepared.interior))
+	for h := range prepared.interior {
+		cells = append(cells, h)
+	}
+	interior, err := CompactCells(cells)
+	if err != nil {
+		return err // LCOV_EXCL_LINE
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if _, ok := idx.polygons[id]; ok {
+		return ErrDuplicateInput
+	}
+	idx.polygons[id] = &polygonIndexEntry{prepared: prepared, interior: interior}
+	for _, h := range interior {
+		m := idx.interior[H3_GET_RESOLUTION(h)]
+		m[h] = append(m[h], id)
+	}
+	for h := range prepared.boundary {
+		idx.boundary[h] = append(idx.boundary[h], id)
+	}
+	return nil
+}
+
+/**
+ * Remove removes a polygon from the index.
+ *
+ * @param id The ID of the polygon.
+ * @return Whether there was a polygon with the ID.
+ */
+func (idx *PolygonIndex) Remove(id string) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	entry, ok := idx.polygons[id]
+	if !ok {
+		return false
+	}
+	delete(idx.polygons, id)
+	for _, h := range entry.interior {
+		_removePolygonID(idx.interior[H3_GET_RESOLUTION(h)], h, id)
+	}
+	for h := range entry.prepared.boundary {
+		_removePolygonID(idx.boundary, h, id)
+	}
+	return true
+}
+
+func _removePolygonID(m map[H3Index][]string, h H3Index, id string) {
+	ids := m[h]
+	for i := range ids {
+		if ids[i] == id {
+			ids = append(ids[:i], ids[i+1:]...)
+			break
+		}
+	}
+	if len(ids) == 0 {
+		delete(m, h)
+	} else {
+		m[h] = ids
+	}
+}
+
+/**
+ * Lookup returns the IDs of the polygons containing a location.
+ *
+ * @param g The location in radians.
+ * @return The IDs in ascending order.
+ */
+func (idx *PolygonIndex) Lookup(g GeoCoord) []string {
+	h := geoToH3(&g, idx.res)
+	out := make([]string, 0)
+	if h == H3_INVALID_INDEX {
+		return out
+	}
+
+	idx.mu.RLock()
+	for r := 0; r <= idx.res; r++ {
+		if m := idx.interior[r]; len(m) > 0 {
+			out = append(out, m[h3ToParent(h, r)]...)
+		}
+	}
+	for _, id := range idx.boundary[h] {
+		if idx.polygons[id].prepared.Contains(g) {
+			out = append(out, id)
+		}
+	}
+	idx.mu.RUnlock()
+
+	sort.Strings(out)
+	return out
+}
+
+/**
+ * LookupCell returns the IDs of the polygons containing the center of a
+ * cell, which is how polyfill decides whether a polygon contains a cell.
+ *
+ * @param h The cell.
+ * @return The IDs in ascending order, or ErrInvalidIndex.
+ */
+func (idx *PolygonIndex) LookupCell(h H3Index) ([]string, error) {
+	if !h3IsValid(h) {
+		return nil, ErrInvalidIndex
+	}
+
+	var g GeoCoord
+	h3ToGeo(h, &g)
+	return idx.Lookup(g), nil
+}
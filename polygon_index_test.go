@@ -0,0 +1,121 @@
+package h3
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolygonIndex(t *testing.T) {
+	var center GeoCoord
+	h3ToGeo(0x8928308280fffff, &center)
+	square := func(dLat float64, dLon float64, size float64) Geofence {
+		lat, lon := center.Lat+dLat, center.Lon+dLon
+		return NewGeofence([]GeoCoord{
+			{lat - size, lon - size}, {lat - size, lon + size},
+			{lat + size, lon + size}, {lat + size, lon - size},
+		})
+	}
+	polygons := map[string]GeoPolygon{
+		"a": NewGeoPolygon(square(0, 0, 0.002)),
+		"b": NewGeoPolygon(square(0.001, 0.001, 0.0015)),
+		"c": NewGeoPolygon(square(0, 0, 0.003), square(0, 0, 0.001)),
+		"d": NewGeoPolygon(NewGeofence(sfVerts)),
+	}
+
+	idx, err := NewPolygonIndex(9)
+	require.NoError(t, err)
+	for id, polygon := range polygons {
+		require.NoError(t, idx.Add(id, polygon))
+	}
+	require.Equal(t, 4, idx.Len())
+
+	// expected IDs of the polygons containing a point, by brute force
+	expected := func(g GeoCoord) []string {
+		out := make([]string, 0)
+		for _, id := range []string{"a", "b", "c", "d"} {
+			polygon, ok := polygons[id]
+			if !ok {
+				continue
+			}
+			bboxes := make([]BBox, polygon.numHoles+1)
+			bboxesFromGeoPolygon(&polygon, bboxes)
+			if pointInsidePolygon(&polygon, bboxes, &g) {
+				out = append(out, id)
+			}
+		}
+		return out
+	}
+
+	r := rand.New(rand.NewSource(41))
+	randomPoint := func() GeoCoord {
+		return GeoCoord{center.Lat + (r.Float64()-0.5)*0.008, center.Lon + (r.Float64()-0.5)*0.008}
+	}
+
+	t.Run("lookup", func(t *testing.T) {
+		found := map[string]bool{}
+		for i := 0; i < 5000; i++ {
+			g := randomPoint()
+			ids := idx.Lookup(g)
+			require.Equal(t, expected(g), ids, "point %v", g)
+			for _, id := range ids {
+				found[id] = true
+			}
+		}
+		require.Len(t, found, 4)
+
+		require.Equal(t, []string{"a", "d"}, idx.Lookup(GeoCoord{0.6585, -2.1375}))
+		require.Empty(t, idx.Lookup(GeoCoord{}))
+	})
+
+	t.Run("lookupCell", func(t *testing.T) {
+		ids, err := idx.LookupCell(0x8928308280fffff)
+		require.NoError(t, err)
+		require.Equal(t, expected(center), ids)
+
+		_, err = idx.LookupCell(H3_INVALID_INDEX)
+		require.Equal(t, ErrInvalidIndex, err)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.Equal(t, ErrDuplicateInput, idx.Add("a", polygons["a"]))
+		require.True(t, idx.Remove("a"))
+		require.False(t, idx.Remove("a"))
+		delete(polygons, "a")
+		require.Equal(t, 3, idx.Len())
+
+		for i := 0; i < 2000; i++ {
+			g := randomPoint()
+			require.Equal(t, expected(g), idx.Lookup(g), "point %v", g)
+		}
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(seed int64) {
+				defer wg.Done()
+				r := rand.New(rand.NewSource(seed))
+				for j := 0; j < 200; j++ {
+					idx.Lookup(GeoCoord{center.Lat + (r.Float64()-0.5)*0.008, center.Lon + (r.Float64()-0.5)*0.008})
+				}
+			}(int64(i))
+		}
+		for i := 0; i < 5; i++ {
+			require.NoError(t, idx.Add("e", NewGeoPolygon(square(-0.001, 0, 0.001))))
+			require.True(t, idx.Remove("e"))
+		}
+		wg.Wait()
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewPolygonIndex(16)
+		require.Equal(t, ErrDomain, err)
+		require.Equal(t, ErrDomain, idx.Add("x", NewGeoPolygon(NewGeofence([]GeoCoord{{0, 0}, {math.NaN(), 0}, {0, 1}}))))
+		require.Equal(t, 3, idx.Len())
+	})
+}
@@ -0,0 +1,360 @@
+package h3
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+/**
+ * @brief Describes a problem with a polygon
+ *
+ * PolygonError unwraps to one of the polygon errors, which in turn wrap
+ * ErrInvalidPolygon, so errors.Is works with either. Loops are numbered 0
+ * for the geofence and i+1 for hole i, and edge v of a loop joins its
+ * vertex v to the next one.
+ */
+type PolygonError struct {
+	Loop        int      ///< loop of the problem
+	Vertex      int      ///< vertex, or first vertex of the edge, of the problem
+	OtherLoop   int      ///< other loop of a self intersection or nested hole
+	OtherVertex int      ///< first vertex of the other edge of a self intersection
+	Point       GeoCoord ///< location of a self intersection, in radians
+	Err         error    ///< kind of problem
+}
+
+/**
+ * reason describes the problem without the common prefix.
+ */
+func (e *PolygonError) reason() string {
+	switch e.Err {
+	case ErrTooFewVertices:
+		return fmt.Sprintf("loop %d has fewer than 3 distinct vertices", e.Loop)
+	case ErrInvalidVertex:
+		return fmt.Sprintf("vertex %d of loop %d is not a valid coordinate", e.Vertex, e.Loop)
+	case ErrClosingVertex:
+		return fmt.Sprintf("last vertex of loop %d repeats the first", e.Loop)
+	case ErrDegenerateEdge:
+		return fmt.Sprintf("edge %d of loop %d has zero length", e.Vertex, e.Loop)
+	case ErrSelfIntersection:
+		return fmt.Sprintf("edge %d of loop %d meets edge %d of loop %d at (%f, %f)",
+			e.Vertex, e.Loop, e.OtherVertex, e.OtherLoop, e.Point.Lat, e.Point.Lon)
+	case ErrHoleOutside:
+		return fmt.Sprintf("hole %d is not inside the geofence", e.Loop-1)
+	case ErrNestedHole:
+		return fmt.Sprintf("hole %d is inside hole %d", e.Loop-1, e.OtherLoop-1)
+	case ErrWrongOrientation:
+		if e.Loop == 0 {
+			return "geofence is clockwise"
+		}
+		return fmt.Sprintf("hole %d is counter-clockwise", e.Loop-1)
+	}
+	return e.Err.Error()
+}
+
+func (e *PolygonError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidPolygon, e.reason())
+}
+
+func (e *PolygonError) Unwrap() error {
+	return e.Err
+}
+
+/**
+ * @brief An edge of a polygon in the plane of longitude and latitude
+ */
+type polygonSegment struct {
+	loop   int
+	vertex int   ///< first vertex of the edge in the loop
+	ord    int   ///< position among the non degenerate edges of the loop
+	count  int   ///< number of non degenerate edges of the loop
+	a, b   Vec2d ///< endpoints, x is the normalized longitude and y the latitude
+	minX   float64
+	maxX   float64
+}
+
+/**
+ * _v2dCross returns the cross product of b - a and c - a, which is positive
+ * when c is to the left of the line from a to b and zero when it is on it.
+ */
+func _v2dCross(a *Vec2d, b *Vec2d, c *Vec2d) float64 {
+	return (b.x-a.x)*(c.y-a.y) - (b.y-a.y)*(c.x-a.x)
+}
+
+/**
+ * _v2dOnSegment returns whether a point on the line through a and b lies
+ * between them.
+ */
+func _v2dOnSegment(a *Vec2d, b *Vec2d, p *Vec2d) bool {
+	return p.x >= math.Min(a.x, b.x) && p.x <= math.Max(a.x, b.x) &&
+		p.y >= math.Min(a.y, b.y) && p.y <= math.Max(a.y, b.y)
+}
+
+/**
+ * _v2dSegmentsMeet returns whether two segments cross or touch.
+ *
+ * @param p0 The first endpoint of the first segment.
+ * @param p1 The second endpoint of the first segment.
+ * @param p2 The first endpoint of the second segment.
+ * @param p3 The second endpoint of the second segment.
+ * @param inter A point where the segments meet.
+ * @return Whether the segments meet.
+ */
+func _v2dSegmentsMeet(p0 *Vec2d, p1 *Vec2d, p2 *Vec2d, p3 *Vec2d, inter *Vec2d) bool {
+	d0 := _v2dCross(p2, p3, p0)
+	d1 := _v2dCross(p2, p3, p1)
+	d2 := _v2dCross(p0, p1, p2)
+	d3 := _v2dCross(p0, p1, p3)
+
+	if ((d0 > 0 && d1 < 0) || (d0 < 0 && d1 > 0)) && ((d2 > 0 && d3 < 0) || (d2 < 0 && d3 > 0)) {
+		_v2dIntersect(p0, p1, p2, p3, inter)
+		return true
+	}
+
+	// Otherwise the segments can only meet at an endpoint, which covers
+	// touching and overlapping collinear segments
+	switch {
+	case d0 == 0 && _v2dOnSegment(p2, p3, p0):
+		*inter = *p0
+	case d1 == 0 && _v2dOnSegment(p2, p3, p1):
+		*inter = *p1
+	case d2 == 0 && _v2dOnSegment(p0, p1, p2):
+		*inter = *p2
+	case d3 == 0 && _v2dOnSegment(p0, p1, p3):
+		*inter = *p3
+	default:
+		return false
+	}
+	return true
+}
+
+/**
+ * _validateLoop reports the problems of a single loop.
+ *
+ * @param loop The loop to check.
+ * @param index The number of the loop in the polygon.
+ * @param errs The problems found so far.
+ * @return The problems including those of the loop, and whether the loop is
+ *         usable for the checks between loops.
+ */
+func _validateLoop(loop *Geofence, index int, errs []*PolygonError) ([]*PolygonError, bool) {
+	for i := 0; i < loop.numVerts; i++ {
+		v := loop.verts[i]
+		if math.IsNaN(v.Lat) || math.IsNaN(v.Lon) || math.IsInf(v.Lon, 0) || math.Abs(v.Lat) > M_PI_2 {
+			return append(errs, &PolygonError{Loop: index, Vertex: i, Err: ErrInvalidVertex}), false
+		}
+	}
+
+	edges := 0
+	for i := 0; i < loop.numVerts; i++ {
+		if !geoAlmostEqual(&loop.verts[i], &loop.verts[(i+1)%loop.numVerts]) {
+			edges++
+		} else if i == loop.numVerts-1 {
+			errs = append(errs, &PolygonError{Loop: index, Vertex: i, Err: ErrClosingVertex})
+		} else {
+			errs = append(errs, &PolygonError{Loop: index, Vertex: i, Err: ErrDegenerateEdge})
+		}
+	}
+	if edges < 3 {
+		return append(errs, &PolygonError{Loop: index, Err: ErrTooFewVertices}), false
+	}
+
+	if isClockwise(loop) != (index > 0) {
+		errs = append(errs, &PolygonError{Loop: index, Err: ErrWrongOrientation})
+	}
+	return errs, true
+}
+
+/**
+ * ValidatePolygon reports the problems of a polygon which make polyfill and
+ * pointInsidePolygon give meaningless results or which GeoJSON forbids.
+ *
+ * Every loop is checked for invalid vertices, repeated vertices and its
+ * orientation: counter-clockwise for the geofence and clockwise for holes.
+ * When all loops have valid vertices, the edges of all loops are checked
+ * for crossings, and when there are none, every hole is checked to be
+ * inside the geofence and outside the other holes.
+ *
+ * @param polygon The geofence and holes defining the area, in radians.
+ * @return The problems found, nil for a valid polygon.
+ */
+func ValidatePolygon(polygon GeoPolygon) []*PolygonError {
+	loops := make([]*Geofence, 0, polygon.numHoles+1)
+	loops = append(loops, &polygon.geofence)
+	for i := 0; i < polygon.numHoles; i++ {
+		loops = append(loops, &polygon.holes[i])
+	}
+
+	var errs []*PolygonError
+	usable := true
+	for i, loop := range loops {
+		var ok bool
+		if errs, ok = _validateLoop(loop, i, errs); !ok {
+			usable = false
+		}
+	}
+	if !usable {
+		return errs
+	}
+
+	bboxes := make([]BBox, len(loops))
+	bboxesFromGeoPolygon(&polygon, bboxes)
+
+	intersections := _polygonIntersections(loops, bboxIsTransmeridian(&bboxes[0]))
+	if len(intersections) > 0 {
+		return append(errs, intersections...)
+	}
+
+	for i := 1; i < len(loops); i++ {
+		v := loops[i].verts[0]
+		if !pointInside(loops[0], &bboxes[0], &v) {
+			errs = append(errs, &PolygonError{Loop: i, Err: ErrHoleOutside})
+		}
+		for j := 1; j < len(loops); j++ {
+			if j != i && pointInside(loops[j], &bboxes[j], &v) {
+				errs = append(errs, &PolygonError{Loop: i, OtherLoop: j, Err: ErrNestedHole})
+			}
+		}
+	}
+	return errs
+}
+
+/**
+ * _polygonIntersections finds the edges which meet other edges than their
+ * neighbors in the loop, sweeping over the edges sorted by longitude.
+ *
+ * @param loops The geofence and the holes.
+ * @param isTransmeridian Whether the geofence crosses the antimeridian.
+ * @return A problem for every pair of edges which meet.
+ */
+func _polygonIntersections(loops []*Geofence, isTransmeridian bool) []*PolygonError {
+	var segments []polygonSegment
+	for l, loop := range loops {
+		start := len(segments)
+		for i := 0; i < loop.numVerts; i++ {
+			a, b := loop.verts[i], loop.verts[(i+1)%loop.numVerts]
+			if geoAlmostEqual(&a, &b) {
+				continue
+			}
+			s := polygonSegment{
+				loop:   l,
+				vertex: i,
+				ord:    len(segments) - start,
+				a:      Vec2d{NORMALIZE_LON(a.Lon, isTransmeridian), a.Lat},
+				b:      Vec2d{NORMALIZE_LON(b.Lon, isTransmeridian), b.Lat},
+			}
+			s.minX, s.maxX = math.Min(s.a.x, s.b.x), math.Max(s.a.x, s.b.x)
+			segments = append(segments, s)
+		}
+		for i := start; i < len(segments); i++ {
+			segments[i].count = len(segments) - start
+		}
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].minX < segments[j].minX })
+
+	var errs []*PolygonError
+	var inter Vec2d
+	for i := range segments {
+		s := &segments[i]
+		for j := i + 1; j < len(segments) && segments[j].minX <= s.maxX; j++ {
+			o := &segments[j]
+			if s.loop == o.loop && ((s.ord+1)%s.count == o.ord || (o.ord+1)%o.count == s.ord) {
+				// Neighbors share a vertex, they only overlap when the
+				// loop turns back on itself
+				if _v2dCross(&s.a, &s.b, &o.a) != 0 || _v2dCross(&s.a, &s.b, &o.b) != 0 ||
+					(s.b.x-s.a.x)*(o.b.x-o.a.x)+(s.b.y-s.a.y)*(o.b.y-o.a.y) >= 0 {
+					continue
+				}
+				if (s.ord+1)%s.count == o.ord {
+					inter = s.b
+				} else {
+					inter = s.a
+				}
+			} else if !_v2dSegmentsMeet(&s.a, &s.b, &o.a, &o.b, &inter) {
+				continue
+			}
+
+			first, second := s, o
+			if first.loop > second.loop || (first.loop == second.loop && first.vertex > second.vertex) {
+				first, second = second, first
+			}
+			lon := inter.x
+			if lon > M_PI {
+				lon -= M_2PI
+			}
+			errs = append(errs, &PolygonError{
+				Loop:        first.loop,
+				Vertex:      first.vertex,
+				OtherLoop:   second.loop,
+				OtherVertex: second.vertex,
+				Point:       GeoCoord{Lat: inter.y, Lon: lon},
+				Err:         ErrSelfIntersection,
+			})
+		}
+	}
+
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].Loop != errs[j].Loop {
+			return errs[i].Loop < errs[j].Loop
+		}
+		if errs[i].Vertex != errs[j].Vertex {
+			return errs[i].Vertex < errs[j].Vertex
+		}
+		if errs[i].OtherLoop != errs[j].OtherLoop {
+			return errs[i].OtherLoop < errs[j].OtherLoop
+		}
+		return errs[i].OtherVertex < errs[j].OtherVertex
+	})
+	return errs
+}
+
+/**
+ * _repairLoop removes repeated vertices from a loop, including a repeated
+ * closing vertex, and winds it in the given direction.
+ *
+ * @param loop The loop to repair, which is not modified.
+ * @param clockwise Whether the loop should be clockwise.
+ * @return The repaired loop.
+ */
+func _repairLoop(loop *Geofence, clockwise bool) Geofence {
+	verts := make([]GeoCoord, 0, loop.numVerts)
+	for i := 0; i < loop.numVerts; i++ {
+		v := loop.verts[i]
+		if len(verts) > 0 && geoAlmostEqual(&verts[len(verts)-1], &v) {
+			continue
+		}
+		verts = append(verts, v)
+	}
+	for len(verts) > 1 && geoAlmostEqual(&verts[0], &verts[len(verts)-1]) {
+		verts = verts[:len(verts)-1]
+	}
+
+	repaired := NewGeofence(verts)
+	if len(verts) >= 3 && isClockwise(&repaired) != clockwise {
+		for i, j := 0, len(verts)-1; i < j; i, j = i+1, j-1 {
+			verts[i], verts[j] = verts[j], verts[i]
+		}
+	}
+	return repaired
+}
+
+/**
+ * RepairPolygon fixes the problems of a polygon which have an unambiguous
+ * fix: repeated vertices are removed, the geofence is made counter-clockwise
+ * and the holes clockwise, and holes left with fewer than 3 vertices are
+ * dropped. Self intersections and misplaced holes are left for
+ * ValidatePolygon to report.
+ *
+ * @param polygon The polygon to repair, which is not modified.
+ * @return The repaired polygon.
+ */
+func RepairPolygon(polygon GeoPolygon) GeoPolygon {
+	geofence := _repairLoop(&polygon.geofence, false)
+	holes := make([]Geofence, 0, polygon.numHoles)
+	for i := 0; i < polygon.numHoles; i++ {
+		if hole := _repairLoop(&polygon.holes[i], true); hole.numVerts >= 3 {
+			holes = append(holes, hole)
+		}
+	}
+	return NewGeoPolygon(geofence, holes...)
+}
@@ -0,0 +1,139 @@
+package h3
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePolygon(t *testing.T) {
+	// a square of size 2*d around (lat, lon), counter-clockwise
+	square := func(lat float64, lon float64, d float64) []GeoCoord {
+		return []GeoCoord{{lat - d, lon - d}, {lat - d, lon + d}, {lat + d, lon + d}, {lat + d, lon - d}}
+	}
+	reversed := func(verts []GeoCoord) []GeoCoord {
+		out := make([]GeoCoord, len(verts))
+		for i := range verts {
+			out[len(verts)-1-i] = verts[i]
+		}
+		return out
+	}
+	requireErrors := func(t *testing.T, errs []*PolygonError, kinds ...error) {
+		require.Len(t, errs, len(kinds), "%v", errs)
+		for i, kind := range kinds {
+			require.True(t, errors.Is(errs[i], kind), "%v is %v", errs[i], kind)
+			require.True(t, errors.Is(errs[i], ErrInvalidPolygon))
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require.Empty(t, ValidatePolygon(NewGeoPolygon(NewGeofence(reversed(sfVerts)))))
+		require.Empty(t, ValidatePolygon(NewGeoPolygon(
+			NewGeofence(square(0, 0, 1)),
+			NewGeofence(reversed(square(0.5, 0.5, 0.1))),
+			NewGeofence(reversed(square(-0.5, -0.5, 0.1))),
+		)))
+		require.Empty(t, ValidatePolygon(NewGeoPolygon(
+			NewGeofence([]GeoCoord{{-0.1, M_PI - 0.1}, {-0.1, -M_PI + 0.1}, {0.1, -M_PI + 0.1}, {0.1, M_PI - 0.1}}),
+			NewGeofence([]GeoCoord{{-0.05, M_PI - 0.05}, {0.05, M_PI - 0.05}, {0.05, -M_PI + 0.05}, {-0.05, -M_PI + 0.05}}),
+		)))
+	})
+
+	t.Run("vertices", func(t *testing.T) {
+		verts := append(square(0, 0, 1), GeoCoord{-1, -1})
+		errs := ValidatePolygon(NewGeoPolygon(NewGeofence(verts)))
+		requireErrors(t, errs, ErrClosingVertex)
+		require.Equal(t, 4, errs[0].Vertex)
+		require.Equal(t, "h3: invalid polygon: last vertex of loop 0 repeats the first", errs[0].Error())
+
+		verts = []GeoCoord{{-1, -1}, {-1, 1}, {-1, 1}, {1, 1}, {1, -1}}
+		errs = ValidatePolygon(NewGeoPolygon(NewGeofence(verts)))
+		requireErrors(t, errs, ErrDegenerateEdge)
+		require.Equal(t, 1, errs[0].Vertex)
+
+		errs = ValidatePolygon(NewGeoPolygon(NewGeofence([]GeoCoord{{0, 0}, {1, 1}, {0, 0}})))
+		requireErrors(t, errs, ErrClosingVertex, ErrTooFewVertices)
+		requireErrors(t, ValidatePolygon(GeoPolygon{}), ErrTooFewVertices)
+
+		errs = ValidatePolygon(NewGeoPolygon(NewGeofence(square(0, 0, 1)), NewGeofence([]GeoCoord{{0, math.NaN()}, {0, 0}, {0.1, 0}})))
+		requireErrors(t, errs, ErrInvalidVertex)
+		require.Equal(t, 1, errs[0].Loop)
+		requireErrors(t, ValidatePolygon(NewGeoPolygon(NewGeofence([]GeoCoord{{0, 0}, {2, 0}, {0, 1}}))), ErrInvalidVertex)
+	})
+
+	t.Run("orientation", func(t *testing.T) {
+		errs := ValidatePolygon(NewGeoPolygon(NewGeofence(reversed(square(0, 0, 1))), NewGeofence(square(0, 0, 0.5))))
+		requireErrors(t, errs, ErrWrongOrientation, ErrWrongOrientation)
+		require.Equal(t, "h3: invalid polygon: geofence is clockwise", errs[0].Error())
+		require.Equal(t, "h3: invalid polygon: hole 0 is counter-clockwise", errs[1].Error())
+	})
+
+	t.Run("selfIntersection", func(t *testing.T) {
+		bowtie := []GeoCoord{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+		errs := ValidatePolygon(NewGeoPolygon(NewGeofence(bowtie)))
+		require.NotEmpty(t, errs)
+		last := errs[len(errs)-1]
+		require.True(t, errors.Is(last, ErrSelfIntersection))
+		require.Equal(t, 1, last.Vertex)
+		require.Equal(t, 3, last.OtherVertex)
+		require.InDelta(t, 0.5, last.Point.Lat, 1e-12)
+		require.InDelta(t, 0.5, last.Point.Lon, 1e-12)
+
+		// two loops touching in a vertex
+		figure8 := []GeoCoord{{0, 0}, {0, 1}, {1, 1}, {0, 1}, {0, 2}, {-1, 1}}
+		errs = ValidatePolygon(NewGeoPolygon(NewGeofence(figure8)))
+		require.NotEmpty(t, errs)
+		require.True(t, errors.Is(errs[len(errs)-1], ErrSelfIntersection))
+
+		// a loop turning back on itself
+		spike := []GeoCoord{{0, 0}, {0, 1}, {0, 2}, {0, 1}, {1, 1}}
+		errs = ValidatePolygon(NewGeoPolygon(NewGeofence(spike)))
+		require.NotEmpty(t, errs)
+		require.True(t, errors.Is(errs[len(errs)-1], ErrSelfIntersection))
+
+		// a hole crossing the geofence
+		errs = ValidatePolygon(NewGeoPolygon(NewGeofence(square(0, 0, 1)), NewGeofence(reversed(square(1, 1, 0.5)))))
+		requireErrors(t, errs, ErrSelfIntersection, ErrSelfIntersection)
+		require.Equal(t, 0, errs[0].Loop)
+		require.Equal(t, 1, errs[0].OtherLoop)
+	})
+
+	t.Run("holes", func(t *testing.T) {
+		errs := ValidatePolygon(NewGeoPolygon(NewGeofence(square(0, 0, 1)), NewGeofence(reversed(square(0, 3, 0.5)))))
+		requireErrors(t, errs, ErrHoleOutside)
+		require.Equal(t, "h3: invalid polygon: hole 0 is not inside the geofence", errs[0].Error())
+
+		errs = ValidatePolygon(NewGeoPolygon(
+			NewGeofence(square(0, 0, 1)),
+			NewGeofence(reversed(square(0, 0, 0.5))),
+			NewGeofence(reversed(square(0, 0, 0.1))),
+		))
+		requireErrors(t, errs, ErrNestedHole)
+		require.Equal(t, 2, errs[0].Loop)
+		require.Equal(t, 1, errs[0].OtherLoop)
+	})
+}
+
+func TestRepairPolygon(t *testing.T) {
+	verts := []GeoCoord{{1, 1}, {1, 1}, {1, -1}, {-1, -1}, {-1, 1}, {1, 1}}
+	hole := []GeoCoord{{0, 0}, {0, 0.5}, {0.5, 0.5}, {0.5, 0}, {0, 0}}
+	polygon := NewGeoPolygon(NewGeofence(verts), NewGeofence(hole), NewGeofence([]GeoCoord{{0, 0}, {0, 0}}))
+	require.NotEmpty(t, ValidatePolygon(polygon))
+
+	repaired := RepairPolygon(polygon)
+	require.Empty(t, ValidatePolygon(repaired))
+	require.Equal(t, []GeoCoord{{1, 1}, {1, -1}, {-1, -1}, {-1, 1}}, repaired.Geofence().Verts())
+	require.Equal(t, []GeoCoord{{0.5, 0}, {0.5, 0.5}, {0, 0.5}, {0, 0}}, repaired.Holes()[0].Verts())
+	require.Len(t, repaired.Holes(), 1)
+	require.Len(t, verts, 6, "input is not modified")
+	require.Equal(t, GeoCoord{1, 1}, verts[0])
+
+	// the cells are those of the original polygon
+	before, err := PolygonToCells(NewGeoPolygon(NewGeofence(sfVerts)), 9)
+	require.NoError(t, err)
+	after, err := PolygonToCells(RepairPolygon(NewGeoPolygon(NewGeofence(append(sfVerts, sfVerts[0])))), 9)
+	require.NoError(t, err)
+	require.ElementsMatch(t, before, after)
+}
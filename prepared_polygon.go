@@ -0,0 +1,174 @@
+package h3
+
+import "math"
+
+/**
+ * @brief Polygon prepared for repeated point in polygon tests
+ *
+ * The polygon is covered with cells of a fixed resolution, split into
+ * interior cells, which are entirely inside the polygon, and boundary cells,
+ * which are crossed by or next to one of its edges. A point is then resolved
+ * with a single geoToH3 and a map lookup, and only points in boundary cells
+ * are tested against the edges of the polygon.
+ *
+ * Finer resolutions make the boundary thinner and the preparation slower; a
+ * resolution with a few thousand cells across the polygon is usually a good
+ * choice. The results are those of pointInsidePolygon.
+ */
+type PreparedPolygon struct {
+	polygon  GeoPolygon
+	bboxes   []BBox ///< bboxes of the geofence and of each hole
+	res      int
+	interior map[H3Index]struct{}
+	boundary map[H3Index]struct{}
+}
+
+/**
+ * NewPreparedPolygon prepares a polygon for point in polygon tests.
+ *
+ * @param polygon The geofence and holes defining the area, in radians. The
+ *                vertices are copied, so that later changes of the caller
+ *                do not affect the prepared polygon.
+ * @param res The resolution of the cover (0-15).
+ * @return The prepared polygon. ErrDomain is returned for an invalid
+ *         resolution, non finite vertices and when the cover would be too
+ *         large.
+ */
+func NewPreparedPolygon(polygon GeoPolygon, res int) (*PreparedPolygon, error) {
+	if res < 0 || res > MAX_H3_RES {
+		return nil, ErrDomain
+	}
+
+	polygon = _geoPolygonCopy(&polygon)
+	p := &PreparedPolygon{
+		polygon:  polygon,
+		bboxes:   make([]BBox, polygon.numHoles+1),
+		res:      res,
+		interior: map[H3Index]struct{}{},
+		boundary: map[H3Index]struct{}{},
+	}
+	if polygon.geofence.IsZero() {
+		return p, nil
+	}
+
+	cells, err := PolygonToCells(polygon, res)
+	if err != nil {
+		return nil, err
+	}
+
+	bboxesFromGeoPolygon(&p.polygon, p.bboxes)
+	if err := p._traceBoundary(&p.polygon.geofence, &p.bboxes[0]); err != nil {
+		return nil, err
+	}
+	for i := 0; i < polygon.numHoles; i++ {
+		if err := p._traceBoundary(&p.polygon.holes[i], &p.bboxes[i+1]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, h := range cells {
+		if _, ok := p.boundary[h]; !ok {
+			p.interior[h] = struct{}{}
+		}
+	}
+	return p, nil
+}
+
+/**
+ * _traceBoundary adds the cells along the edges of a loop, and their
+ * neighbors, to the boundary cells.
+ *
+ * The edges are followed as pointInside sees them, straight in latitude and
+ * longitude, with samples closer than half the edge length of the cells.
+ * Adding the neighbors of the sampled cells covers the cells an edge clips
+ * between two samples, as well as points close to a cell border that
+ * geoToH3 assigns to the other side.
+ *
+ * @param geofence The loop to trace.
+ * @param bbox The bbox of the loop.
+ * @return ErrDomain when the loop needs too many samples.
+ */
+func (p *PreparedPolygon) _traceBoundary(geofence *Geofence, bbox *BBox) error {
+	isTransmeridian := bboxIsTransmeridian(bbox)
+	step := edgeLengthKm(p.res) / 2 / EARTH_RADIUS_KM
+	neighbors := make([]H3Index, maxKringSize(1))
+
+	last := H3_INVALID_INDEX
+	for i := 0; i < geofence.numVerts; i++ {
+		a := geofence.verts[i]
+		b := geofence.verts[(i+1)%geofence.numVerts]
+		aLon := NORMALIZE_LON(a.Lon, isTransmeridian)
+		dLat := b.Lat - a.Lat
+		dLon := NORMALIZE_LON(b.Lon, isTransmeridian) - aLon
+
+		// The length of the edge in the plane bounds its length on the
+		// sphere, where the longitudes are closer together
+		samples := math.Ceil(math.Hypot(dLat, dLon) / step)
+		if samples > MAX_SLICE_CELLS {
+			return ErrDomain
+		}
+
+		for s := 0; s <= int(samples); s++ {
+			t := 0.0
+			if samples > 0 {
+				t = float64(s) / samples
+			}
+			g := GeoCoord{Lat: a.Lat + dLat*t, Lon: aLon + dLon*t}
+			if g.Lon > M_PI {
+				g.Lon -= M_2PI
+			}
+
+			h := geoToH3(&g, p.res)
+			if h == last {
+				continue
+			}
+			last = h
+
+			for j := range neighbors {
+				neighbors[j] = H3_INVALID_INDEX
+			}
+			kRing(h, 1, neighbors)
+			for _, n := range neighbors {
+				if n != H3_INVALID_INDEX {
+					p.boundary[n] = struct{}{}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+/**
+ * Contains returns whether the polygon contains the location.
+ *
+ * @param g The location in radians.
+ * @return Whether the location is inside, as pointInsidePolygon.
+ */
+func (p *PreparedPolygon) Contains(g GeoCoord) bool {
+	if p.polygon.geofence.IsZero() || !bboxContains(&p.bboxes[0], &g) {
+		return false
+	}
+
+	h := geoToH3(&g, p.res)
+	if _, ok := p.interior[h]; ok {
+		return true
+	}
+	if _, ok := p.boundary[h]; ok {
+		return pointInsidePolygon(&p.polygon, p.bboxes, &g)
+	}
+	return false
+}
+
+/**
+ * Polygon returns a copy of the prepared polygon.
+ */
+func (p *PreparedPolygon) Polygon() GeoPolygon {
+	return _geoPolygonCopy(&p.polygon)
+}
+
+/**
+ * Resolution returns the resolution of the cover.
+ */
+func (p *PreparedPolygon) Resolution() int {
+	return p.res
+}
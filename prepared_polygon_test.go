@@ -0,0 +1,124 @@
+package h3
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreparedPolygon(t *testing.T) {
+	// compares Contains with pointInsidePolygon at random points around the
+	// polygon and returns the share of the points inside which are resolved
+	// by the interior cells
+	check := func(t *testing.T, polygon GeoPolygon, res int) float64 {
+		p, err := NewPreparedPolygon(polygon, res)
+		require.NoError(t, err)
+		require.Equal(t, res, p.Resolution())
+
+		bboxes := make([]BBox, polygon.numHoles+1)
+		bboxesFromGeoPolygon(&polygon, bboxes)
+		bbox := bboxes[0]
+		width := bbox.east - bbox.west
+		if bboxIsTransmeridian(&bbox) {
+			width += M_2PI
+		}
+		height := bbox.north - bbox.south
+
+		r := rand.New(rand.NewSource(40))
+		inside, interior := 0, 0
+		for i := 0; i < 20000; i++ {
+			g := GeoCoord{
+				Lat: bbox.south - height*0.1 + r.Float64()*height*1.2,
+				Lon: bbox.west - width*0.1 + r.Float64()*width*1.2,
+			}
+			if g.Lon > M_PI {
+				g.Lon -= M_2PI
+			}
+			contains := pointInsidePolygon(&polygon, bboxes, &g)
+			require.Equal(t, contains, p.Contains(g), "point %v", g)
+			if !contains {
+				continue
+			}
+			inside++
+			if _, ok := p.interior[geoToH3(&g, res)]; ok {
+				interior++
+			}
+		}
+		return float64(interior) / float64(inside)
+	}
+
+	t.Run("sf", func(t *testing.T) {
+		polygon := NewGeoPolygon(NewGeofence(sfVerts))
+		share := check(t, polygon, 9)
+		require.True(t, share > 0.8, "most points in interior cells, %f", share)
+		check(t, polygon, 7)
+	})
+
+	t.Run("hole", func(t *testing.T) {
+		var center GeoCoord
+		h3ToGeo(0x8928308280fffff, &center)
+		square := func(size float64) Geofence {
+			return NewGeofence([]GeoCoord{
+				{center.Lat - size, center.Lon - size}, {center.Lat - size, center.Lon + size},
+				{center.Lat + size, center.Lon + size}, {center.Lat + size, center.Lon - size},
+			})
+		}
+		check(t, NewGeoPolygon(square(0.002), square(0.0005)), 10)
+	})
+
+	t.Run("transmeridian", func(t *testing.T) {
+		polygon := NewGeoPolygon(NewGeofence([]GeoCoord{
+			{0.01, M_PI - 0.01}, {0.01, -M_PI + 0.01}, {-0.01, -M_PI + 0.01}, {-0.01, M_PI - 0.01},
+		}))
+		check(t, polygon, 7)
+	})
+
+	t.Run("copied", func(t *testing.T) {
+		// changing the vertices of the caller, or those returned, does not
+		// change the prepared polygon
+		verts := append([]GeoCoord(nil), sfVerts...)
+		center := GeoCoord{0.659, -2.136}
+		hole := []GeoCoord{
+			{center.Lat - 0.0002, center.Lon - 0.0002}, {center.Lat - 0.0002, center.Lon + 0.0002},
+			{center.Lat + 0.0002, center.Lon + 0.0002}, {center.Lat + 0.0002, center.Lon - 0.0002},
+		}
+		polygon := NewGeoPolygon(NewGeofence(verts), NewGeofence(hole))
+		p, err := NewPreparedPolygon(polygon, 9)
+		require.NoError(t, err)
+		outside := GeoCoord{Lat: center.Lat + 0.0004, Lon: center.Lon}
+		require.False(t, p.Contains(center))
+		require.True(t, p.Contains(outside))
+
+		for i := range verts {
+			verts[i] = GeoCoord{}
+		}
+		for i := range hole {
+			hole[i] = GeoCoord{}
+		}
+		returned := p.Polygon()
+		returned.Geofence().Verts()[0] = GeoCoord{}
+
+		require.Equal(t, sfVerts, p.Polygon().Geofence().Verts())
+		require.False(t, p.Contains(center))
+		require.True(t, p.Contains(outside))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		p, err := NewPreparedPolygon(GeoPolygon{}, 5)
+		require.NoError(t, err)
+		require.False(t, p.Contains(GeoCoord{}))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewPreparedPolygon(NewGeoPolygon(NewGeofence(sfVerts)), 16)
+		require.Equal(t, ErrDomain, err)
+		_, err = NewPreparedPolygon(NewGeoPolygon(NewGeofence([]GeoCoord{{0, 0}, {math.Inf(1), 0}, {0, 1}})), 5)
+		require.Equal(t, ErrDomain, err)
+
+		p, err := NewPreparedPolygon(NewGeoPolygon(NewGeofence(sfVerts)), 5)
+		require.NoError(t, err)
+		require.False(t, p.Contains(GeoCoord{Lat: math.NaN()}))
+	})
+}
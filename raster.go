@@ -0,0 +1,214 @@
+package h3
+
+/** Arrangement of the local ij coordinates in the rows and columns of a Raster. */
+type RasterLayout int
+
+/** Raster layouts */
+const (
+	/** row is the j offset and column the i offset from the origin; the
+	 * hexagonal neighborhood leaves two opposite corners of the array empty */
+	RASTER_AXIAL RasterLayout = iota
+	/** rows as in RASTER_AXIAL, with every second row shifted by half a
+	 * column so that the columns line up spatially */
+	RASTER_OFFSET
+)
+
+/** State of a single position of a Raster. */
+type RasterCellState int
+
+/** Raster position states */
+const (
+	RASTER_CELL        RasterCellState = iota ///< position holds a cell
+	RASTER_OUTSIDE                            ///< position is farther than the radius from the origin
+	RASTER_DELETED                            ///< position is in a deleted pentagon subsequence
+	RASTER_UNAVAILABLE                        ///< position can not be unfolded from the origin
+)
+
+/**
+ * @brief Dense 2D view of the neighborhood of a cell
+ *
+ * Both arrays have 2 * Radius + 1 rows and columns. Positions that do not
+ * hold a cell are H3_INVALID_INDEX in Cells, with the reason in States.
+ */
+type Raster struct {
+	Origin H3Index             ///< cell at the center of the raster
+	Radius int                 ///< grid radius covered by the raster
+	Layout RasterLayout        ///< arrangement of rows and columns
+	Cells  [][]H3Index         ///< cells by row and column
+	States [][]RasterCellState ///< state of every position
+	origin CoordIJ             ///< local ij coordinates of the origin
+}
+
+/**
+ * Floor division by two, also for negative numbers.
+ */
+func _floorHalf(v int) int {
+	if v < 0 {
+		return -((-v + 1) / 2)
+	}
+	return v / 2
+}
+
+/**
+ * Whether an ij offset is within the given grid distance. Neighbors in ij
+ * are (±1, 0), (0, ±1) and ±(1, 1).
+ */
+func _ijWithinDistance(di int, dj int, radius int) bool {
+	if (di >= 0) == (dj >= 0) {
+		if di < 0 {
+			di, dj = -di, -dj
+		}
+		return di <= radius && dj <= radius
+	}
+	if di < 0 {
+		di = -di
+	}
+	if dj < 0 {
+		dj = -dj
+	}
+	return di+dj <= radius
+}
+
+/**
+ * Converts an ij offset from the origin to a row and column.
+ */
+func (r *Raster) _position(di int, dj int) (int, int) {
+	if r.Layout == RASTER_OFFSET {
+		return dj + r.Radius, di - _floorHalf(dj) + r.Radius
+	}
+	return dj + r.Radius, di + r.Radius
+}
+
+/**
+ * Converts a row and column to an ij offset from the origin.
+ */
+func (r *Raster) _offset(row int, col int) (int, int) {
+	dj := row - r.Radius
+	if r.Layout == RASTER_OFFSET {
+		return col - r.Radius + _floorHalf(dj), dj
+	}
+	return col - r.Radius, dj
+}
+
+/**
+ * NewRaster lays out the cells within grid distance radius of the origin in a
+ * dense 2D array, using the local ij coordinates of the origin.
+ *
+ * @param origin Cell at the center of the raster.
+ * @param radius Grid radius, radius >= 0.
+ * @param layout Arrangement of the rows and columns.
+ * @return The raster.
+ */
+func NewRaster(origin H3Index, radius int, layout RasterLayout) (*Raster, error) {
+	if radius < 0 || (layout != RASTER_AXIAL && layout != RASTER_OFFSET) {
+		return nil, ErrDomain
+	}
+	originIJ, err := CellToLocalIJ(origin, origin)
+	if err != nil {
+		return nil, err
+	}
+
+	size := 2*radius + 1
+	r := &Raster{
+		Origin: origin,
+		Radius: radius,
+		Layout: layout,
+		Cells:  make([][]H3Index, size),
+		States: make([][]RasterCellState, size),
+		origin: originIJ,
+	}
+
+	for row := 0; row < size; row++ {
+		r.Cells[row] = make([]H3Index, size)
+		r.States[row] = make([]RasterCellState, size)
+
+		for col := 0; col < size; col++ {
+			di, dj := r._offset(row, col)
+			if !_ijWithinDistance(di, dj, radius) {
+				r.States[row][col] = RASTER_OUTSIDE
+				continue
+			}
+
+			cell, err := LocalIJToCell(origin, CoordIJ{I: originIJ.I + di, J: originIJ.J + dj})
+			switch err {
+			case nil:
+				r.Cells[row][col] = cell
+				r.States[row][col] = RASTER_CELL
+			case ErrPentagon:
+				r.States[row][col] = RASTER_DELETED
+			default:
+				r.States[row][col] = RASTER_UNAVAILABLE
+			}
+		}
+	}
+
+	return r, nil
+}
+
+/**
+ * Position returns the row and column of a cell in the raster.
+ *
+ * @param h The cell to locate.
+ * @return The row and column, and whether the cell is in the raster.
+ */
+func (r *Raster) Position(h H3Index) (int, int, bool) {
+	ij, err := CellToLocalIJ(r.Origin, h)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	row, col := r._position(ij.I-r.origin.I, ij.J-r.origin.J)
+	size := 2*r.Radius + 1
+	if row < 0 || row >= size || col < 0 || col >= size || r.Cells[row][col] != h {
+		return 0, 0, false
+	}
+	return row, col, true
+}
+
+/**
+ * Values arranges per cell values like the cells of the raster.
+ *
+ * @param values Values by cell.
+ * @param fill Value for positions without a cell or without a value.
+ * @return The values by row and column.
+ */
+func (r *Raster) Values(values map[H3Index]float64, fill float64) [][]float64 {
+	out := make([][]float64, len(r.Cells))
+	for row := range r.Cells {
+		out[row] = make([]float64, len(r.Cells[row]))
+		for col, cell := range r.Cells[row] {
+			v, ok := values[cell]
+			if r.States[row][col] != RASTER_CELL || !ok {
+				v = fill
+			}
+			out[row][col] = v
+		}
+	}
+	return out
+}
+
+/**
+ * ToMap is the inverse of Values: it reads the values at the positions
+ * holding a cell back into a map by cell.
+ *
+ * @param values Values by row and column, of the same shape as the raster.
+ * @return Values by cell.
+ */
+func (r *Raster) ToMap(values [][]float64) (map[H3Index]float64, error) {
+	if len(values) != len(r.Cells) {
+		return nil, ErrDomain
+	}
+
+	out := make(map[H3Index]float64)
+	for row := range r.Cells {
+		if len(values[row]) != len(r.Cells[row]) {
+			return nil, ErrDomain
+		}
+		for col, cell := range r.Cells[row] {
+			if r.States[row][col] == RASTER_CELL {
+				out[cell] = values[row][col]
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package h3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRaster(t *testing.T) {
+	t.Run("hexagon", func(t *testing.T) {
+		origin := H3Index(0x8928308280fffff)
+		disk, err := GridDisk(origin, 3)
+		require.NoError(t, err)
+
+		for _, layout := range []RasterLayout{RASTER_AXIAL, RASTER_OFFSET} {
+			r, err := NewRaster(origin, 3, layout)
+			require.NoError(t, err)
+			require.Len(t, r.Cells, 7)
+			require.Equal(t, origin, r.Cells[3][3])
+
+			var cells []H3Index
+			for row := range r.Cells {
+				require.Len(t, r.Cells[row], 7)
+				for col, cell := range r.Cells[row] {
+					if r.States[row][col] != RASTER_CELL {
+						require.Equal(t, H3_INVALID_INDEX, cell)
+						continue
+					}
+					cells = append(cells, cell)
+
+					gotRow, gotCol, ok := r.Position(cell)
+					require.True(t, ok)
+					require.Equal(t, row, gotRow)
+					require.Equal(t, col, gotCol)
+				}
+			}
+			require.ElementsMatch(t, disk, cells)
+		}
+	})
+
+	t.Run("pentagon", func(t *testing.T) {
+		var pentagon H3Index
+		setH3Index(&pentagon, 5, 4, 0)
+
+		r, err := NewRaster(pentagon, 2, RASTER_AXIAL)
+		require.NoError(t, err)
+
+		deleted := 0
+		for row := range r.States {
+			for _, state := range r.States[row] {
+				if state == RASTER_DELETED || state == RASTER_UNAVAILABLE {
+					deleted++
+				}
+			}
+		}
+		require.True(t, deleted > 0, "positions in the deleted subsequence are marked")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewRaster(0x8928308280fffff, -1, RASTER_AXIAL)
+		require.Equal(t, ErrDomain, err)
+		_, err = NewRaster(0x8928308280fffff, 1, RasterLayout(7))
+		require.Equal(t, ErrDomain, err)
+		_, err = NewRaster(H3_INVALID_INDEX, 1, RASTER_AXIAL)
+		require.Error(t, err)
+	})
+}
+
+func TestRasterValues(t *testing.T) {
+	origin := H3Index(0x8928308280fffff)
+	r, err := NewRaster(origin, 2, RASTER_OFFSET)
+	require.NoError(t, err)
+
+	values := map[H3Index]float64{}
+	disk, err := GridDisk(origin, 2)
+	require.NoError(t, err)
+	for i, h := range disk {
+		values[h] = float64(i)
+	}
+
+	grid := r.Values(values, -1)
+	require.Equal(t, float64(0), grid[2][2])
+
+	back, err := r.ToMap(grid)
+	require.NoError(t, err)
+	require.Equal(t, values, back)
+
+	_, err = r.ToMap(grid[1:])
+	require.Equal(t, ErrDomain, err)
+}
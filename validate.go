@@ -0,0 +1,124 @@
+package h3
+
+import (
+	"fmt"
+)
+
+/**
+ * @brief Describes why an index is invalid
+ *
+ * IndexError unwraps to one of the ErrInvalid* errors, which in turn
+ * wrap ErrInvalidIndex, so errors.Is works with either.
+ */
+type IndexError struct {
+	Index H3Index ///< the rejected index
+	Res   int     ///< resolution of the offending digit, 0 if not about a digit
+	Value int     ///< value of the offending field
+	Err   error   ///< kind of failure
+}
+
+/**
+ * reason describes the failure without mentioning the index.
+ */
+func (e *IndexError) reason() string {
+	switch e.Err {
+	case ErrInvalidMode:
+		return fmt.Sprintf("mode %d is not H3_HEXAGON_MODE", e.Value)
+	case ErrInvalidBaseCell:
+		return fmt.Sprintf("base cell %d is out of range", e.Value)
+	case ErrInvalidResolution:
+		return fmt.Sprintf("resolution %d is out of range", e.Value)
+	case ErrDeletedSubsequence:
+		return fmt.Sprintf("digit at res %d is in the deleted K axes subsequence of pentagon base cell %d", e.Res, e.Value)
+	case ErrInvalidDigit:
+		return fmt.Sprintf("digit %d at res %d is not a valid direction", e.Value, e.Res)
+	case ErrInvalidUnusedDigit:
+		return fmt.Sprintf("unused digit %d at res %d is not 7", e.Value, e.Res)
+	case ErrInvalidEdgeMode:
+		return fmt.Sprintf("mode %d is not H3_UNIEDGE_MODE", e.Value)
+	case ErrInvalidEdgeDirection:
+		return fmt.Sprintf("edge direction %d is not a neighbor direction of the origin", e.Value)
+	}
+	return e.Err.Error()
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("h3: invalid index %s: %s", h3ToString(e.Index), e.reason())
+}
+
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+/**
+ * Validate explains why an index is not a valid cell. It performs the same
+ * checks as h3IsValid, in the same order, and reports the first failure.
+ *
+ * @param h The H3 index to validate.
+ * @return nil for valid cells, otherwise an *IndexError.
+ */
+func Validate(h H3Index) error {
+	if mode := H3_GET_MODE(h); mode != H3_HEXAGON_MODE {
+		return &IndexError{Index: h, Value: int(mode), Err: ErrInvalidMode}
+	}
+	baseCell := H3_GET_BASE_CELL(h)
+	if baseCell < 0 || baseCell >= NUM_BASE_CELLS {
+		return &IndexError{Index: h, Value: baseCell, Err: ErrInvalidBaseCell}
+	}
+	res := H3_GET_RESOLUTION(h)
+	if res < 0 || res > MAX_H3_RES {
+		return &IndexError{Index: h, Value: res, Err: ErrInvalidResolution}
+	}
+	foundFirstNonZeroDigit := false
+	for r := 1; r <= res; r++ {
+		digit := H3_GET_INDEX_DIGIT(h, r)
+		if !foundFirstNonZeroDigit && digit != CENTER_DIGIT {
+			foundFirstNonZeroDigit = true
+			if _isBaseCellPentagon(baseCell) && digit == K_AXES_DIGIT {
+				return &IndexError{Index: h, Res: r, Value: baseCell, Err: ErrDeletedSubsequence}
+			}
+		}
+
+		if digit < CENTER_DIGIT || digit >= NUM_DIGITS {
+			return &IndexError{Index: h, Res: r, Value: int(digit), Err: ErrInvalidDigit}
+		}
+	}
+
+	for r := res + 1; r <= MAX_H3_RES; r++ {
+		digit := H3_GET_INDEX_DIGIT(h, r)
+		if digit != INVALID_DIGIT {
+			return &IndexError{Index: h, Res: r, Value: int(digit), Err: ErrInvalidUnusedDigit}
+		}
+	}
+
+	return nil
+}
+
+/**
+ * ValidateDirectedEdge explains why an index is not a valid unidirectional
+ * edge. It performs the same checks as h3UnidirectionalEdgeIsValid.
+ *
+ * @param edge The unidirectional edge H3Index to validate.
+ * @return nil for valid edges, otherwise an *IndexError. Errors of the
+ *         origin cell are reported as by Validate.
+ */
+func ValidateDirectedEdge(edge H3Index) error {
+	if mode := H3_GET_MODE(edge); mode != H3_UNIEDGE_MODE {
+		return &IndexError{Index: edge, Value: int(mode), Err: ErrInvalidEdgeMode}
+	}
+
+	neighborDirection := Direction(H3_GET_RESERVED_BITS(edge))
+	if neighborDirection <= CENTER_DIGIT || neighborDirection >= NUM_DIGITS {
+		return &IndexError{Index: edge, Value: int(neighborDirection), Err: ErrInvalidEdgeDirection}
+	}
+
+	origin := getOriginH3IndexFromUnidirectionalEdge(edge)
+	if err := Validate(origin); err != nil {
+		return err
+	}
+	if h3IsPentagon(origin) && neighborDirection == K_AXES_DIGIT {
+		return &IndexError{Index: edge, Value: int(neighborDirection), Err: ErrInvalidEdgeDirection}
+	}
+
+	return nil
+}
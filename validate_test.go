@@ -0,0 +1,115 @@
+package h3
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	requireIndexError := func(t *testing.T, err error, kind error, res int, value int) {
+		require.True(t, errors.Is(err, kind), "%v is %v", err, kind)
+		require.True(t, errors.Is(err, ErrInvalidIndex), "%v is ErrInvalidIndex", err)
+
+		var indexErr *IndexError
+		require.True(t, errors.As(err, &indexErr))
+		require.Equal(t, res, indexErr.Res)
+		require.Equal(t, value, indexErr.Value)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, Validate(0x8928308280fffff))
+	})
+
+	t.Run("mode", func(t *testing.T) {
+		h := H3Index(0x8928308280fffff)
+		H3_SET_MODE(&h, H3_UNIEDGE_MODE)
+		requireIndexError(t, Validate(h), ErrInvalidMode, 0, 2)
+	})
+
+	t.Run("baseCell", func(t *testing.T) {
+		h := H3Index(0x8928308280fffff)
+		H3_SET_BASE_CELL(&h, 127)
+		err := Validate(h)
+		requireIndexError(t, err, ErrInvalidBaseCell, 0, 127)
+		require.Equal(t, "h3: invalid index 89fe308280fffff: base cell 127 is out of range", err.Error())
+	})
+
+	t.Run("deletedSubsequence", func(t *testing.T) {
+		var h H3Index
+		setH3Index(&h, 3, 4, 0)
+		H3_SET_INDEX_DIGIT(&h, 2, K_AXES_DIGIT)
+		requireIndexError(t, Validate(h), ErrDeletedSubsequence, 2, 4)
+	})
+
+	t.Run("digit", func(t *testing.T) {
+		h := H3Index(0x8928308280fffff)
+		H3_SET_INDEX_DIGIT(&h, 5, INVALID_DIGIT)
+		requireIndexError(t, Validate(h), ErrInvalidDigit, 5, 7)
+	})
+
+	t.Run("unusedDigit", func(t *testing.T) {
+		h := H3Index(0x8928308280fffff)
+		H3_SET_INDEX_DIGIT(&h, 15, CENTER_DIGIT)
+		requireIndexError(t, Validate(h), ErrInvalidUnusedDigit, 15, 0)
+	})
+
+	t.Run("agreesWithH3IsValid", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 10000; i++ {
+			h := H3Index(0x8928308280fffff)
+			for j := 0; j < 1+r.Intn(3); j++ {
+				h ^= 1 << uint(r.Intn(64))
+			}
+			require.Equal(t, h3IsValid(h), Validate(h) == nil, "%x", h)
+		}
+	})
+}
+
+func TestValidateDirectedEdge(t *testing.T) {
+	edges := make([]H3Index, 6)
+	getH3UnidirectionalEdgesFromHexagon(0x8928308280fffff, edges)
+	for _, edge := range edges {
+		require.NoError(t, ValidateDirectedEdge(edge))
+	}
+
+	t.Run("mode", func(t *testing.T) {
+		err := ValidateDirectedEdge(0x8928308280fffff)
+		require.True(t, errors.Is(err, ErrInvalidEdgeMode))
+	})
+
+	t.Run("direction", func(t *testing.T) {
+		edge := edges[0]
+		H3_SET_RESERVED_BITS(&edge, int(INVALID_DIGIT))
+		require.True(t, errors.Is(ValidateDirectedEdge(edge), ErrInvalidEdgeDirection))
+
+		var pentagon H3Index
+		setH3Index(&pentagon, 5, 4, 0)
+		H3_SET_MODE(&pentagon, H3_UNIEDGE_MODE)
+		H3_SET_RESERVED_BITS(&pentagon, int(K_AXES_DIGIT))
+		require.True(t, errors.Is(ValidateDirectedEdge(pentagon), ErrInvalidEdgeDirection))
+	})
+
+	t.Run("origin", func(t *testing.T) {
+		edge := edges[0]
+		H3_SET_INDEX_DIGIT(&edge, 15, CENTER_DIGIT)
+		require.True(t, errors.Is(ValidateDirectedEdge(edge), ErrInvalidUnusedDigit))
+	})
+
+	t.Run("agreesWithH3UnidirectionalEdgeIsValid", func(t *testing.T) {
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 10000; i++ {
+			edge := edges[r.Intn(6)]
+			for j := 0; j < 1+r.Intn(2); j++ {
+				edge ^= 1 << uint(r.Intn(64))
+			}
+			if ValidateDirectedEdge(edge) == nil {
+				require.True(t, h3UnidirectionalEdgeIsValid(edge), "%x", edge)
+			} else if Validate(getOriginH3IndexFromUnidirectionalEdge(edge)) == nil {
+				require.False(t, h3UnidirectionalEdgeIsValid(edge), "%x", edge)
+			}
+		}
+	})
+}
@@ -39,3 +39,27 @@ func _geoToVec3d(geo *GeoCoord, v *Vec3d) {
 	v.x = math.Cos(geo.Lon) * r
 	v.y = math.Sin(geo.Lon) * r
 }
+
+/**
+ * Calculate the dot product of two 3D vectors.
+ *
+ * @param v1 The first vector.
+ * @param v2 The second vector.
+ * @return The dot product.
+ */
+func _vec3dDot(v1 *Vec3d, v2 *Vec3d) float64 {
+	return v1.x*v2.x + v1.y*v2.y + v1.z*v2.z
+}
+
+/**
+ * Calculate the cross product of two 3D vectors.
+ *
+ * @param v1 The first vector.
+ * @param v2 The second vector.
+ * @param out The cross product v1 x v2.
+ */
+func _vec3dCross(v1 *Vec3d, v2 *Vec3d, out *Vec3d) {
+	out.x = v1.y*v2.z - v1.z*v2.y
+	out.y = v1.z*v2.x - v1.x*v2.z
+	out.z = v1.x*v2.y - v1.y*v2.x
+}
@@ -0,0 +1,137 @@
+package h3
+
+/** Invalid vertex number */
+const INVALID_VERTEX_NUM = -1
+
+/**
+ * @brief The faces in each axial direction of a given pentagon base cell
+ */
+type pentagonDirectionFaces struct {
+	baseCell int                 ///< base cell number
+	faces    [NUM_PENT_VERTS]int ///< face numbers for each axial direction, in order, starting with J
+}
+
+/** @brief Pentagon direction to face mapping */
+var pentagonDirectionFacesTable = [NUM_PENTAGONS]pentagonDirectionFaces{
+	{4, [NUM_PENT_VERTS]int{4, 0, 2, 1, 3}},
+	{14, [NUM_PENT_VERTS]int{6, 11, 2, 7, 1}},
+	{24, [NUM_PENT_VERTS]int{5, 10, 1, 6, 0}},
+	{38, [NUM_PENT_VERTS]int{7, 12, 3, 8, 2}},
+	{49, [NUM_PENT_VERTS]int{9, 14, 0, 5, 4}},
+	{58, [NUM_PENT_VERTS]int{8, 13, 4, 9, 3}},
+	{63, [NUM_PENT_VERTS]int{11, 6, 15, 10, 16}},
+	{72, [NUM_PENT_VERTS]int{12, 7, 16, 11, 17}},
+	{83, [NUM_PENT_VERTS]int{10, 5, 19, 14, 15}},
+	{97, [NUM_PENT_VERTS]int{13, 8, 17, 12, 18}},
+	{107, [NUM_PENT_VERTS]int{14, 9, 18, 13, 19}},
+	{117, [NUM_PENT_VERTS]int{15, 19, 17, 18, 16}},
+}
+
+/** @brief Hexagon direction to vertex number relationships (same face) */
+var directionToVertexNumHex = [NUM_DIGITS]int{INVALID_VERTEX_NUM, 3, 1, 2, 5, 4, 0}
+
+/** @brief Pentagon direction to vertex number relationships (same face) */
+var directionToVertexNumPent = [NUM_DIGITS]int{INVALID_VERTEX_NUM, INVALID_VERTEX_NUM, 1, 2, 4, 3, 0}
+
+/**
+ * Find the number of 60 degree ccw rotations of a base cell on a face, or -1
+ * when the base cell is not on the face.
+ *
+ * @param baseCell The base cell.
+ * @param face The icosahedron face.
+ * @return The number of rotations.
+ */
+func _baseCellToCCWrot60(baseCell int, face int) int {
+	if face < 0 || face >= NUM_ICOSA_FACES {
+		return -1
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				if faceIjkBaseCells[face][i][j][k].baseCell == baseCell {
+					return faceIjkBaseCells[face][i][j][k].ccwRot60
+				}
+			}
+		}
+	}
+	return -1
+}
+
+/**
+ * Get the number of CCW rotations of the cell's vertex numbers compared to
+ * the directional layout of its neighbors.
+ *
+ * @param cell The cell.
+ * @return Number of CCW rotations for the cell.
+ */
+func vertexRotations(cell H3Index) int {
+	// Get the face and other info for the origin
+	var fijk FaceIJK
+	_h3ToFaceIjk(cell, &fijk)
+	baseCell := H3_GET_BASE_CELL(cell)
+	cellLeadingDigit := _h3LeadingNonZeroDigit(cell)
+
+	// get the base cell face
+	var baseFijk FaceIJK
+	_baseCellToFaceIjk(baseCell, &baseFijk)
+
+	ccwRot60 := _baseCellToCCWrot60(baseCell, fijk.face)
+
+	if _isBaseCellPentagon(baseCell) {
+		// Find the appropriate direction-to-face mapping
+		var dirFaces pentagonDirectionFaces
+		for p := 0; p < NUM_PENTAGONS; p++ {
+			if pentagonDirectionFacesTable[p].baseCell == baseCell {
+				dirFaces = pentagonDirectionFacesTable[p]
+				break
+			}
+		}
+
+		// additional CCW rotation for polar neighbors or IK neighbors
+		if fijk.face != baseFijk.face &&
+			(_isBaseCellPolarPentagon(baseCell) ||
+				fijk.face == dirFaces.faces[IK_AXES_DIGIT-J_AXES_DIGIT]) {
+			ccwRot60 = (ccwRot60 + 1) % 6
+		}
+
+		// Check whether the cell crosses a deleted pentagon subsequence
+		if cellLeadingDigit == JK_AXES_DIGIT &&
+			fijk.face == dirFaces.faces[IK_AXES_DIGIT-J_AXES_DIGIT] {
+			// Crosses from JK to IK: Rotate CW
+			ccwRot60 = (ccwRot60 + 5) % 6
+		} else if cellLeadingDigit == IK_AXES_DIGIT &&
+			fijk.face == dirFaces.faces[JK_AXES_DIGIT-J_AXES_DIGIT] {
+			// Crosses from IK to JK: Rotate CCW
+			ccwRot60 = (ccwRot60 + 1) % 6
+		}
+	}
+	return ccwRot60
+}
+
+/**
+ * Get the first vertex number for a given direction. The neighbor in this
+ * direction is located between this vertex number and the next number in
+ * sequence.
+ *
+ * @param origin The cell.
+ * @param direction The direction of the neighbor.
+ * @return The number for the first topological vertex, or
+ *         INVALID_VERTEX_NUM if the direction is not valid for this cell.
+ */
+func vertexNumForDirection(origin H3Index, direction Direction) int {
+	isPentagon := h3IsPentagon(origin)
+	// Check for invalid directions
+	if direction <= CENTER_DIGIT || direction >= INVALID_DIGIT ||
+		(isPentagon && direction == K_AXES_DIGIT) {
+		return INVALID_VERTEX_NUM
+	}
+
+	// Determine the vertex rotations for this cell
+	rotations := vertexRotations(origin)
+
+	// Find the appropriate vertex, rotating CCW if necessary
+	if isPentagon {
+		return (directionToVertexNumPent[direction] + NUM_PENT_VERTS - rotations) % NUM_PENT_VERTS
+	}
+	return (directionToVertexNumHex[direction] + NUM_HEX_VERTS - rotations) % NUM_HEX_VERTS
+}
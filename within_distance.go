@@ -0,0 +1,137 @@
+package h3
+
+import "math"
+
+/** Which cells are within a distance of a point */
+type WithinMode int
+
+/** Modes of CellsWithinDistance */
+const (
+	WITHIN_CENTER  WithinMode = iota ///< cells whose center is within the distance
+	WITHIN_OVERLAP                   ///< cells of which any part is within the distance
+)
+
+/**
+ * _capCellEstimate returns an estimated upper bound of the number of cells
+ * overlapping a spherical cap, from the area of the cap widened by the size
+ * of the cells over the area of the smallest cells.
+ *
+ * @param radius Radius of the cap, in radians.
+ * @param res The resolution of the cells (0-15).
+ * @return The estimate, at most the number of cells of the resolution.
+ */
+func _capCellEstimate(radius float64, res int) float64 {
+	pentagons := make([]H3Index, 0)
+	getPentagonIndexes(res, &pentagons)
+
+	// The largest cells are about twice as wide as the pentagons
+	pentagonRadius := _hexRadiusKm(pentagons[0]) / EARTH_RADIUS_KM
+	pentagonArea := 2.59807621135 * pentagonRadius * pentagonRadius
+	capArea := 2 * M_PI * (1 - math.Cos(math.Min(radius+4*pentagonRadius, M_PI)))
+	return math.Min(math.Ceil(capArea/pentagonArea), float64(numHexagons(res)))
+}
+
+/**
+ * _cellDistRads returns the great circle distance between a point and the
+ * nearest point of a cell. The edges of the boundary of a cell, including
+ * those split at icosahedron edges, are great circle arcs.
+ *
+ * @param h The cell.
+ * @param containing The cell of the point, at the resolution of h.
+ * @param p The point.
+ * @return The distance in radians, 0 when the cell contains the point.
+ */
+func _cellDistRads(h H3Index, containing H3Index, p *GeoCoord) float64 {
+	if h == containing {
+		return 0
+	}
+	var gb GeoBoundary
+	h3ToGeoBoundary(h, &gb)
+	dist := math.Inf(1)
+	for i := 0; i < gb.numVerts; i++ {
+		dist = math.Min(dist, _geoArcDistRads(p, &gb.Verts[i], &gb.Verts[(i+1)%gb.numVerts]))
+	}
+	return dist
+}
+
+/**
+ * CellsWithinDistance produces the cells of a resolution within a great
+ * circle distance of a point.
+ *
+ * The grid is searched ring by ring from the cell of the point, and the
+ * search stops at the first ring of which no cell reaches the circle: the
+ * circle is connected, so no cell beyond the ring can reach it either. The
+ * grid distance searched thus follows from the actual cell boundaries, and
+ * pentagons and distortion are accounted for.
+ *
+ * @param center The center of the circle, in radians.
+ * @param radius The radius of the circle, non-negative.
+ * @param unit The unit of radius.
+ * @param res The resolution of the cells (0-15).
+ * @param mode Whether the centers of the cells, or any of their area, must
+ *             be within the radius.
+ * @return The cells in order of increasing grid distance from the cell of
+ *         the center. ErrDomain is returned for an invalid argument and when
+ *         the circle may hold more than MAX_SLICE_CELLS cells.
+ */
+func CellsWithinDistance(center GeoCoord, radius float64, unit DistanceUnit, res int, mode WithinMode) ([]H3Index, error) {
+	rads := _unitToRads(radius, unit)
+	if math.IsNaN(rads) || rads < 0 || res < 0 || res > MAX_H3_RES || mode < WITHIN_CENTER || mode > WITHIN_OVERLAP {
+		return nil, ErrDomain
+	}
+	if !_geoIsFinite(&center) {
+		return nil, ErrDomain
+	}
+	origin := geoToH3(&center, res)
+	if origin == H3_INVALID_INDEX {
+		return nil, ErrDomain
+	}
+	if _capCellEstimate(rads, res) > MAX_SLICE_CELLS {
+		return nil, ErrDomain
+	}
+
+	within := func(h H3Index) (inside bool, overlaps bool) {
+		var g GeoCoord
+		h3ToGeo(h, &g)
+		if _geoDistRads(&center, &g) <= rads {
+			return true, true
+		}
+		return false, _cellDistRads(h, origin, &center) <= rads
+	}
+
+	var out []H3Index
+	ring := []H3Index{origin}
+	seen := map[H3Index]struct{}{origin: {}}
+	for len(ring) > 0 {
+		overlapping := false
+		for _, h := range ring {
+			inside, overlaps := within(h)
+			if inside || (mode == WITHIN_OVERLAP && overlaps) {
+				out = append(out, h)
+			}
+			overlapping = overlapping || overlaps
+		}
+		if !overlapping {
+			break
+		}
+
+		var next []H3Index
+		for _, h := range ring {
+			for dir := 0; dir < 6; dir++ {
+				rotations := 0
+				neighbor := h3NeighborRotations(h, DIRECTIONS[dir], &rotations)
+				if neighbor == H3_INVALID_INDEX {
+					// deleted k subsequence of a pentagon
+					continue
+				}
+				if _, ok := seen[neighbor]; ok {
+					continue
+				}
+				seen[neighbor] = struct{}{}
+				next = append(next, neighbor)
+			}
+		}
+		ring = next
+	}
+	return out, nil
+}
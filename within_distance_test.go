@@ -0,0 +1,184 @@
+package h3
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCellsWithinDistance(t *testing.T) {
+	var pentagon H3Index
+	setH3Index(&pentagon, 4, 38, 0)
+	var pentagonCenter GeoCoord
+	h3ToGeo(pentagon, &pentagonCenter)
+
+	cases := map[string]struct {
+		center GeoCoord
+		radius float64 // km
+		res    int
+	}{
+		"sanFrancisco": {*GeoFromWGS84(37.7749, -122.4194), 2, 9},
+		"pentagon":     {pentagonCenter, 300, 4},
+		"nearPentagon": {GeoCoord{Lat: pentagonCenter.Lat + 0.02, Lon: pentagonCenter.Lon}, 150, 5},
+		"pole":         {GeoCoord{Lat: M_PI_2 - 0.001, Lon: 1}, 500, 3},
+		"antimeridian": {GeoCoord{Lat: 0.3, Lon: M_PI - 1e-6}, 50, 5},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			disk := requireWithinDistance(t, c.center, c.radius, c.res)
+			origin := geoToH3(&c.center, c.res)
+			centers := 0
+			for _, h := range disk {
+				var g GeoCoord
+				h3ToGeo(h, &g)
+				if _geoDistKm(&c.center, &g) <= c.radius {
+					centers++
+				}
+			}
+			cells, err := CellsWithinDistance(c.center, c.radius*1000, UNIT_M, c.res, WITHIN_OVERLAP)
+			require.NoError(t, err)
+			require.True(t, len(cells) > centers)
+			require.Equal(t, origin, cells[0])
+		})
+	}
+
+	t.Run("aroundPentagons", func(t *testing.T) {
+		r := rand.New(rand.NewSource(50))
+		for i := 0; i < 40; i++ {
+			res := 1 + r.Intn(6)
+			pentagons := make([]H3Index, 0)
+			getPentagonIndexes(res, &pentagons)
+			var pentagonCenter, center GeoCoord
+			h3ToGeo(pentagons[r.Intn(len(pentagons))], &pentagonCenter)
+
+			// within a few cells of the pentagon, with a radius of a few cells
+			edge := edgeLengthKm(res) / EARTH_RADIUS_KM
+			_geoAzDistanceRads(&pentagonCenter, r.Float64()*2*M_PI, r.Float64()*3*edge, &center)
+			radius := (0.2 + 3*r.Float64()) * edgeLengthKm(res)
+			requireWithinDistance(t, center, radius, res)
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		center := *GeoFromWGS84(37.7749, -122.4194)
+		cells, err := CellsWithinDistance(center, 0, UNIT_RADS, 9, WITHIN_OVERLAP)
+		require.NoError(t, err)
+		require.Equal(t, []H3Index{geoToH3(&center, 9)}, cells)
+
+		cells, err = CellsWithinDistance(center, 0, UNIT_RADS, 9, WITHIN_CENTER)
+		require.NoError(t, err)
+		require.Empty(t, cells)
+	})
+
+	t.Run("wholeSphere", func(t *testing.T) {
+		cells, err := CellsWithinDistance(GeoCoord{}, M_PI, UNIT_RADS, 0, WITHIN_CENTER)
+		require.NoError(t, err)
+		require.Len(t, cells, int(numHexagons(0)))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		center := *GeoFromWGS84(37.7749, -122.4194)
+		for _, err := range []error{
+			func() error { _, err := CellsWithinDistance(center, -1, UNIT_KM, 9, WITHIN_CENTER); return err }(),
+			func() error { _, err := CellsWithinDistance(center, math.NaN(), UNIT_KM, 9, WITHIN_CENTER); return err }(),
+			func() error { _, err := CellsWithinDistance(center, 1, DistanceUnit(42), 9, WITHIN_CENTER); return err }(),
+			func() error { _, err := CellsWithinDistance(center, 1, UNIT_KM, 16, WITHIN_CENTER); return err }(),
+			func() error { _, err := CellsWithinDistance(center, 1, UNIT_KM, 9, WithinMode(42)); return err }(),
+			func() error {
+				_, err := CellsWithinDistance(GeoCoord{Lat: math.NaN()}, 1, UNIT_KM, 9, WITHIN_CENTER)
+				return err
+			}(),
+			func() error {
+				_, err := CellsWithinDistance(GeoCoord{Lon: math.Inf(1)}, 1, UNIT_KM, 9, WITHIN_CENTER)
+				return err
+			}(),
+			func() error { _, err := CellsWithinDistance(center, 20000, UNIT_KM, 15, WITHIN_CENTER); return err }(),
+		} {
+			require.Equal(t, ErrDomain, err)
+		}
+	})
+}
+
+/**
+ * sampledBoundaryDistRads returns the distance from p to the nearest of
+ * points sampled along the boundary of a cell, and the most by which it may
+ * exceed the distance to the boundary itself: half the step between the
+ * samples.
+ */
+func sampledBoundaryDistRads(h H3Index, p *GeoCoord) (float64, float64) {
+	const samples = 64
+
+	var gb GeoBoundary
+	h3ToGeoBoundary(h, &gb)
+	dist, slack := math.Inf(1), 0.0
+	for i := 0; i < gb.numVerts; i++ {
+		a, b := &gb.Verts[i], &gb.Verts[(i+1)%gb.numVerts]
+		length := _geoDistRads(a, b)
+		az := _geoAzimuthRads(a, b)
+		slack = math.Max(slack, length/samples/2)
+		for j := 0; j < samples; j++ {
+			var g GeoCoord
+			_geoAzDistanceRads(a, az, length*float64(j)/samples, &g)
+			dist = math.Min(dist, _geoDistRads(p, &g))
+		}
+	}
+	return dist, slack
+}
+
+/**
+ * requireWithinDistance checks both modes of CellsWithinDistance against a
+ * filter of a disk much larger than needed. Cells overlap the circle when
+ * they contain the center or a sample of their boundary is within the
+ * radius, and do not when no sample is within the radius and the sampling
+ * slack. Cells in between are not decided.
+ *
+ * @return The disk filtered.
+ */
+func requireWithinDistance(t *testing.T, center GeoCoord, radius float64, res int) []H3Index {
+	origin := geoToH3(&center, res)
+	k := int(math.Ceil(2*radius/edgeLengthKm(res))) + 2
+	disk, err := GridDisk(origin, k)
+	require.NoError(t, err)
+
+	rads := radius / EARTH_RADIUS_KM
+	var centers []H3Index
+	overlapping := map[H3Index]bool{}
+	for _, h := range disk {
+		var g GeoCoord
+		h3ToGeo(h, &g)
+		if _geoDistKm(&center, &g) <= radius {
+			centers = append(centers, h)
+		}
+		if dist, slack := sampledBoundaryDistRads(h, &center); h == origin || dist <= rads {
+			overlapping[h] = true
+		} else if dist-slack > rads {
+			overlapping[h] = false
+		}
+	}
+	require.NotEmpty(t, overlapping)
+
+	cells, err := CellsWithinDistance(center, radius, UNIT_KM, res, WITHIN_CENTER)
+	require.NoError(t, err)
+	require.ElementsMatch(t, centers, cells, "centers within %f km of %v at res %d", radius, center, res)
+
+	cells, err = CellsWithinDistance(center, radius, UNIT_KM, res, WITHIN_OVERLAP)
+	require.NoError(t, err)
+	inDisk := map[H3Index]bool{}
+	for _, h := range disk {
+		inDisk[h] = true
+	}
+	found := map[H3Index]bool{}
+	for _, h := range cells {
+		require.True(t, inDisk[h], "%x beyond the disk searched", h)
+		overlaps, decided := overlapping[h]
+		require.True(t, !decided || overlaps, "%x does not overlap %f km of %v at res %d", h, radius, center, res)
+		found[h] = true
+	}
+	for h, overlaps := range overlapping {
+		require.True(t, !overlaps || found[h], "%x overlaps %f km of %v at res %d", h, radius, center, res)
+	}
+	return disk
+}